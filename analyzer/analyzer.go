@@ -5,12 +5,15 @@ package analyzer
 import (
 	"bytes"
 	"encoding/gob"
+	"errors"
+	"fmt"
 	"io"
 	"os"
 	"runtime"
 	"sort"
 	"strings"
 	"sync"
+	"unicode/utf8"
 
 	"github.com/pracj3am/wordle-solver/dict"
 	"github.com/pracj3am/wordle-solver/odds"
@@ -21,28 +24,41 @@ import (
 const defaultOddsThreshold = 150
 
 // LuckStat = distribuce počtu zbylých možných odpovědí pro daný tip.
+// Weights je tentýž histogram vážený DictionaryWord.Weight a Sum jeho součet;
+// luck.gob bez Weights (starší) se počítá z Histogram.
 type LuckStat struct {
 	Histogram map[int]int
 	Sum       float64
+	Weights   map[int]float64
+}
+
+// Tile = zpětná vazba na jedné pozici tipu.
+type Tile struct {
+	Letter string `json:"letter"` // jak ho ukáže hra (na zelené/modré s diakritikou řešení)
+	Color  string `json:"color"`  // green, blue, orange, grey
 }
 
 // Row = výsledek analýzy jednoho tipu. -1 znamená "nedostupné" ("–").
 type Row struct {
-	Word        string  `json:"word"`
-	Left        int     `json:"left"`        // všechna platná zbývající slova
-	LeftAnswers int     `json:"leftAnswers"` // z toho možné odpovědi
-	Difficulty  int     `json:"difficulty"`  // -1 = "–"
-	IQ          int     `json:"iq"`          // 0..100, nebo -1
-	Luck        float64 `json:"luck"`        // %, nebo -1
-	Answers     []string `json:"answers"`    // zbývající možné odpovědi (cap, s diakritikou)
-	Others      []string `json:"others"`     // ostatní zbývající platná slova (cap)
+	Word        string   `json:"word"`
+	Feedback    []Tile   `json:"feedback"`    // barvy, podle kterých se filtrovala slova
+	Left        int      `json:"left"`        // všechna platná zbývající slova
+	LeftAnswers int      `json:"leftAnswers"` // z toho možné odpovědi
+	Difficulty  int      `json:"difficulty"`  // -1 = "–"
+	IQ          int      `json:"iq"`          // 0..100, nebo -1
+	Luck        float64  `json:"luck"`        // %, nebo -1
+	Answers     []string `json:"answers"`     // zbývající možné odpovědi (cap, s diakritikou)
+	Others      []string `json:"others"`      // ostatní zbývající platná slova (cap)
+
+	HardModeViolation string `json:"hardModeViolation,omitempty"` // proč by tip v hard mode nešel
+	Error             string `json:"error,omitempty"`             // tip (nebo řešení) má znak mimo abecedu; metriky chybí
 }
 
 // wordsCap = max. počet slov v každém seznamu (zbytek se zkrátí, frontend ukáže „…+N").
 const wordsCap = 200
 
 // Engine drží načtený slovník a (volitelně) předpočítané hodnoty pro 1. tah
-// z luck.gob (jinak nil). Viz CONTEXT.md (Used, luck.gob).
+// z luck.gob (jinak nil). Viz CONTEXT.md (odpovědi, luck.gob).
 type Engine struct {
 	dict       *dict.Dictionary
 	luck       map[string]*LuckStat
@@ -52,40 +68,31 @@ type Engine struct {
 	// OddsThreshold: nad tolik kandidátů se metriky pro DALŠÍ tah nepočítají živě
 	// (výpočet je ~O(N³)) a vyjdou jako "–". Viz CONTEXT.md.
 	OddsThreshold int
+
+	// Rules: která písmena hra nerozlišuje (nulová hodnota = bez diakritiky).
+	// luck.gob musí být vygenerovaný se stejnými pravidly (GenerateLuck).
+	Rules pr.Rules
 }
 
-// loadDict načte slovník s Used=true pro slova, která NEJSOU možná odpověď.
+// loadDict načte slovník; odpovědi jsou jen slova z answers (ostatní jsou
+// jen platné tipy).
 func loadDict(dictPath string, answers []string) (*dict.Dictionary, error) {
-	all, err := dict.LoadHistory(dictPath) // všechna slova ze souboru (s diakritikou)
+	d, err := dict.LoadDictionary(dictPath, nil)
 	if err != nil {
 		return nil, err
 	}
-	return dict.LoadDictionary(dictPath, answersToHistory(all, answers))
+	d.SetAnswers(answers)
+	return d, nil
 }
 
-// loadDictFromBytes je verze loadDict bez souborového systému (WASM); vstup se
-// čte dvakrát, proto bytes.NewReader pokaždé znovu.
+// loadDictFromBytes je verze loadDict bez souborového systému (WASM).
 func loadDictFromBytes(dictData []byte, answers []string) (*dict.Dictionary, error) {
-	all, err := dict.LoadHistoryFromReader(bytes.NewReader(dictData))
+	d, err := dict.LoadDictionaryFromReader(bytes.NewReader(dictData), nil)
 	if err != nil {
 		return nil, err
 	}
-	return dict.LoadDictionaryFromReader(bytes.NewReader(dictData), answersToHistory(all, answers))
-}
-
-// answersToHistory vrací mapu Used=true pro slova, která NEJSOU možná odpověď.
-func answersToHistory(all map[string]bool, answers []string) map[string]bool {
-	ans := make(map[string]bool, len(answers))
-	for _, a := range answers {
-		ans[dict.StripDiacritic(a)] = true
-	}
-	history := make(map[string]bool, len(all))
-	for w := range all {
-		if !ans[dict.StripDiacritic(w)] {
-			history[w] = true
-		}
-	}
-	return history
+	d.SetAnswers(answers)
+	return d, nil
 }
 
 // NewEngine načte slovník; je-li luckPath != "" a soubor existuje, načte i
@@ -120,6 +127,34 @@ func NewEngineFromBytes(dictData, luckData []byte, answers []string) (*Engine, e
 	return e, nil
 }
 
+// NewEngineFromCompiled je NewEngineFromBytes pro slovník z dict.Compile.
+// Odpovědi určí answers (ne příznaky uložené při kompilaci) a historie se
+// zahodí, takže engine počítá stejně jako z NewEngineFromBytes se stejnými
+// answers (a s luck.gob z GenerateLuck s nimi).
+func NewEngineFromCompiled(dictData, luckData []byte, answers []string) (*Engine, error) {
+	d, err := dict.LoadCompiled(dictData)
+	if err != nil {
+		return nil, err
+	}
+	d.SetHistory(nil)
+	d.SetAnswers(answers)
+	e := &Engine{dict: d, OddsThreshold: defaultOddsThreshold}
+	if len(luckData) > 0 {
+		if luck, sr, sh, err := LoadLuckFromReader(bytes.NewReader(luckData)); err == nil {
+			e.luck, e.skillRobot, e.skillHuman = luck, sr, sh
+		}
+	}
+	return e, nil
+}
+
+// Dictionary vrátí slovník enginu; Add, Remove, MarkUsed a SetHistory na něm
+// jsou bezpečné i během Analyze (každý průchod slovníkem vidí jeden stav).
+// Rozbor staré hry: SetHistory(historie.Before(den)).
+// Předpočítané luck.gob pro 1. tah se tím nezmění.
+func (e *Engine) Dictionary() *dict.Dictionary {
+	return e.dict
+}
+
 func LoadLuck(path string) (map[string]*LuckStat, map[string]*odds.Skill, map[string]*odds.Skill, error) {
 	f, err := os.Open(path)
 	if err != nil {
@@ -148,12 +183,13 @@ func LoadLuckFromReader(r io.Reader) (map[string]*LuckStat, map[string]*odds.Ski
 
 // GenerateLuck předpočítá luck.gob pro 1. tah: pro každé slovo fondu spočítá
 // (paralelně) calcOdds nad celým fondem → histogram štěstí + váhy, z vah skill.
-func GenerateLuck(dictPath string, answers []string, outPath string) error {
+// rules = pravidla, se kterými se gob bude používat (Engine.Rules).
+func GenerateLuck(dictPath string, answers []string, outPath string, rules pr.Rules) error {
 	d, err := loadDict(dictPath, answers)
 	if err != nil {
 		return err
 	}
-	base := pr.NewProgress(5, d)
+	base := pr.NewProgress(d.Size, d, rules)
 	_, _, all := base.WordsLeft(true) // všechna slova (bez omezení)
 
 	type result struct {
@@ -180,7 +216,7 @@ func GenerateLuck(dictPath string, answers []string, outPath string) error {
 	robotW := make([]odds.WeightedWord, len(all))
 	humanW := make([]odds.WeightedWord, len(all))
 	for i, r := range results {
-		luck[dict.StripDiacritic(r.word)] = r.luck
+		luck[all[i].WithoutDiacritics] = r.luck
 		robotW[i] = odds.WeightedWord{Word: r.word, Weight: r.robot}
 		humanW[i] = odds.WeightedWord{Word: r.word, Weight: r.human}
 	}
@@ -206,90 +242,183 @@ func GenerateLuck(dictPath string, answers []string, outPath string) error {
 
 // calcOdds = port reference CalculateOdds: simuluje tip "word" proti každé možné
 // odpovědi z "all" a vrací průměrný počet zbylých slov (human=z odpovědí, robot=ze
-// všech) a histogram štěstí.
+// všech) a histogram štěstí. Průměry i histogram jsou vážené DictionaryWord.Weight
+// řešení. Zbylá slova = skupina z Partition/SplitRevealed se stejnou zpětnou
+// vazbou, takže stačí jeden průchod (~O(N) na tip místo O(N²)).
 func calcOdds(word *dict.DictionaryWord, all []*dict.DictionaryWord, base *pr.Progress) (human, robot float64, luck *LuckStat) {
-	var sum, sumNotUsed float64
-	var count, countNotUsed int
-	luck = &LuckStat{Histogram: make(map[int]int)}
-	for _, dw := range all {
-		var counter, counterNotUsed int
-		if dw.WithoutDiacritics != word.WithoutDiacritics {
-			p := base.Clone()
-			p.ResetRound()
-			p.Guess(word.Word, dw.Word)
-			counter, counterNotUsed, _ = p.WordsLeft(false)
-		}
-		sum += float64(counter)
-		count++
-		if !dw.Used { // dw je možná odpověď
-			luck.Histogram[counterNotUsed]++
-			luck.Sum++
-			sumNotUsed += float64(counterNotUsed)
-			countNotUsed++
+	var sum, sumNotUsed, weight, weightNotUsed float64
+	luck = &LuckStat{Histogram: make(map[int]int), Weights: make(map[int]float64)}
+	for pat, bucket := range base.Partition(word.Word, all) {
+		for _, group := range pr.SplitRevealed(pat, bucket) {
+			groupCounter, groupNotUsed := base.CountWords(group)
+			for _, dw := range group {
+				counter, counterNotUsed := groupCounter, groupNotUsed
+				if base.Rules().SameWord(dw, word) {
+					counter, counterNotUsed = 0, 0 // tip byl řešení
+				}
+				sum += dw.Weight * float64(counter)
+				weight += dw.Weight
+				if dw.Possible() { // dw je možná odpověď
+					luck.Histogram[counterNotUsed]++
+					luck.Weights[counterNotUsed] += dw.Weight
+					luck.Sum += dw.Weight
+					sumNotUsed += dw.Weight * float64(counterNotUsed)
+					weightNotUsed += dw.Weight
+				}
+			}
 		}
 	}
-	robot = sum / float64(count)
-	if countNotUsed > 0 {
-		human = sumNotUsed / float64(countNotUsed)
+	if weight > 0 {
+		robot = sum / weight
+	}
+	if weightNotUsed > 0 {
+		human = sumNotUsed / weightNotUsed
 	}
 	return
 }
 
-// luckPct = port reference: procento řešení, u nichž by tip dopadl hůř (vyšší = víc štěstí).
+// luckPct = port reference: procento řešení (vážené), u nichž by tip dopadl hůř
+// (vyšší = víc štěstí).
 func luckPct(ls *LuckStat, counterNotUsed int) float64 {
 	if ls == nil || ls.Sum == 0 {
 		return -1
 	}
-	var sumBetter, sumWorse, countBetter int
+	var sumBetter, sumWorse float64
+	var countBetter int
 	for histLeft, histCount := range ls.Histogram {
+		w := float64(histCount)
+		if ls.Weights != nil {
+			w = ls.Weights[histLeft]
+		}
 		if histLeft <= counterNotUsed {
-			sumBetter += histCount
+			sumBetter += w
 			if histLeft > 0 {
 				countBetter++
 			}
 		} else {
-			sumWorse += histCount
+			sumWorse += w
 		}
 	}
 	if sumWorse > 0 || countBetter > 1 {
-		return 100 - 100*float64(sumBetter)/ls.Sum
+		return 100 - 100*sumBetter/ls.Sum
 	}
 	return -1
 }
 
-// Analyze pro každý tip (základní písmena bez diakritiky) spočítá metriky.
-// solution = denní slovo (může mít diakritiku); zpětnou vazbu odvodí progress.Guess.
+// feedback převede zpětnou vazbu pat na tip guess na dlaždice pro JSON.
+func feedback(guess, solution string, pat pr.Pattern) []Tile {
+	tiles := make([]Tile, 0, pat.Len())
+	for i, písmeno := range []rune(pr.Reveal(guess, solution, pat)) {
+		if i >= pat.Len() {
+			break
+		}
+		tiles = append(tiles, Tile{Letter: string(písmeno), Color: pat.At(i).String()})
+	}
+	return tiles
+}
+
+// checkHardMode ověří tip v hard mode. Tipy chodí obvykle bez diakritiky,
+// takže stačí, když vyhoví některá akcentovaná varianta ze slovníku. Porušení
+// vrátí jako *pr.HardModeError, jiné chyby (tip nejde ověřit) tak, jak jsou.
+func (e *Engine) checkHardMode(progress *pr.Progress, raw string) error {
+	stripped, err := e.dict.Alphabet.Strip(raw)
+	if err != nil {
+		return err
+	}
+	guess := []rune(stripped)
+	variants := []string{raw}
+	e.dict.Walk(
+		func(pos, index int) bool {
+			return pos < len(guess) && e.dict.Alphabet.Letters[index] == guess[pos]
+		},
+		func(_ []int, dw *dict.DictionaryWord) bool {
+			if dw.Word != raw {
+				variants = append(variants, dw.Word)
+			}
+			return true
+		},
+	)
+
+	for _, v := range variants {
+		verr := progress.CheckHardMode(v)
+		if verr == nil {
+			return nil
+		}
+		var hard *pr.HardModeError
+		if err == nil || errors.As(verr, &hard) {
+			err = verr
+		}
+	}
+	return err
+}
+
+// normalize převede slovo přes Alphabet.Normalize a ověří jeho délku.
+func (e *Engine) normalize(raw string) (string, error) {
+	word, err := e.dict.Alphabet.Normalize(raw)
+	if err == nil && utf8.RuneCountInString(word) != e.dict.Size {
+		err = fmt.Errorf("%q: %d letters, want %d", word, utf8.RuneCountInString(word), e.dict.Size)
+	}
+	return word, err
+}
+
+// Analyze pro každý tip (obvykle základní písmena bez diakritiky, na které
+// záleží jen podle e.Rules) spočítá metriky. solution = denní slovo (může mít
+// diakritiku); zpětnou vazbu odvodí progress.Guess. Tipy i řešení projdou
+// Alphabet.Normalize a musí mít délku slov slovníku; tip, který tím neprojde
+// (nebo všechny tipy, neprojde-li řešení), dostane jen Row.Error.
 func (e *Engine) Analyze(guesses []string, solution string) []Row {
-	progress := pr.NewProgress(5, e.dict)
+	solution, solutionErr := e.normalize(solution)
+	progress := pr.NewProgress(e.dict.Size, e.dict, e.Rules)
+	rules := progress.Rules()
 	rows := make([]Row, 0, len(guesses))
 
 	// luckMap/skillMap = metriky pro AKTUÁLNÍ tip (spočítané na konci minulého kola)
-	luckMap := e.luck        // z luck.gob (pokrývá 1. tah – luck i difficulty/IQ), nebo nil
+	luckMap := e.luck // z luck.gob (pokrývá 1. tah – luck i difficulty/IQ), nebo nil
 	skillMap := e.skillHuman
 
 	// bez gobu: fallback – štěstí 1. tahu nad plným fondem (difficulty/IQ zůstane "–")
 	if luckMap == nil && len(guesses) > 0 {
-		_, _, full := progress.WordsLeft(true)
-		g0 := dict.StripDiacritic(guesses[0])
-		gw := &dict.DictionaryWord{Word: g0, WithoutDiacritics: g0}
-		_, _, ls := calcOdds(gw, full, progress)
-		luckMap = map[string]*LuckStat{g0: ls}
+		if w0, err := e.normalize(guesses[0]); err == nil {
+			_, _, full := progress.WordsLeft(true)
+			g0, _ := e.dict.Alphabet.Strip(w0) // normalize ověřil písmena
+			gw := &dict.DictionaryWord{Word: w0, WithoutDiacritics: g0}
+			_, _, ls := calcOdds(gw, full, progress)
+			luckMap = map[string]*LuckStat{g0: ls}
+		}
 	}
 
 	for _, raw := range guesses {
-		guess := dict.StripDiacritic(raw)
-		if len(guess) == 0 {
+		word, err := e.normalize(raw)
+		if len(word) == 0 {
+			continue
+		}
+		// Row.Word je tip bez diakritiky i u chybných řádků; znaky mimo
+		// abecedu v něm zůstanou (chyba je v Row.Error)
+		guess, _ := e.dict.Alphabet.Strip(word)
+		if err == nil {
+			err = solutionErr
+		}
+		if err != nil {
+			rows = append(rows, Row{Word: strings.ToUpper(guess), Error: err.Error(), Difficulty: -1, IQ: -1, Luck: -1})
 			continue
 		}
+		gw := &dict.DictionaryWord{Word: word, WithoutDiacritics: guess}
+		hardMode := e.checkHardMode(progress, word)
 		progress.ResetRound()
-		progress.Guess(guess, solution)
+		pat := progress.Guess(gw.Word, solution)
 		counter, counterNotUsed, wordsLeft := progress.WordsLeft(true)
-		if counter == 1 && len(wordsLeft) > 0 && wordsLeft[0].WithoutDiacritics == guess {
+		if counter == 1 && len(wordsLeft) > 0 && rules.SameWord(wordsLeft[0], gw) {
 			counter, counterNotUsed = 0, 0 // tip byl řešení
 		}
 
-		row := Row{Word: strings.ToUpper(guess), Left: counter, LeftAnswers: counterNotUsed,
-			Difficulty: -1, IQ: -1, Luck: -1}
+		row := Row{Word: strings.ToUpper(guess), Feedback: feedback(gw.Word, solution, pat),
+			Left: counter, LeftAnswers: counterNotUsed, Difficulty: -1, IQ: -1, Luck: -1}
+		var hard *pr.HardModeError
+		if errors.As(hardMode, &hard) {
+			row.HardModeViolation = hard.Error()
+		} else if hardMode != nil {
+			row.Error = hardMode.Error()
+		}
 		if luckMap != nil {
 			row.Luck = luckPct(luckMap[guess], counterNotUsed)
 		}
@@ -305,11 +434,11 @@ func (e *Engine) Analyze(guesses []string, solution string) []Row {
 		if counter > 0 {
 			seen := make(map[string]bool, len(wordsLeft))
 			for _, dw := range wordsLeft {
-				if seen[dw.WithoutDiacritics] {
+				if seen[rules.Key(dw)] {
 					continue
 				}
-				seen[dw.WithoutDiacritics] = true
-				if dw.Used { // ostatní platná (není možná odpověď)
+				seen[rules.Key(dw)] = true
+				if !dw.Possible() { // ostatní platná (není možná odpověď)
 					if len(row.Others) < wordsCap {
 						row.Others = append(row.Others, dw.Word)
 					}
@@ -0,0 +1,119 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pracj3am/wordle-solver/dict"
+)
+
+const testDict = "krava\nkráva\nbarva\nmleko\npivko\nvrána\n"
+
+func testEngine(t *testing.T, answers ...string) *Engine {
+	t.Helper()
+	e, err := NewEngineFromBytes([]byte(testDict), nil, answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func tiles(letters string, colors ...string) []Tile {
+	var ts []Tile
+	for i, písmeno := range []rune(letters) {
+		ts = append(ts, Tile{Letter: string(písmeno), Color: colors[i]})
+	}
+	return ts
+}
+
+func TestFeedback(t *testing.T) {
+	e := testEngine(t, "krava", "barva", "mleko", "pivko", "vrana")
+	rows := e.Analyze([]string{"barva", "vrana", "KRAVA"}, "kráva")
+	want := [][]Tile{
+		tiles("barva", "grey", "orange", "orange", "green", "green"),
+		// zelená ukáže diakritiku řešení
+		tiles("vrána", "orange", "green", "green", "grey", "green"),
+		tiles("kráva", "green", "green", "green", "green", "green"),
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("Analyze: %d rows, want %d", len(rows), len(want))
+	}
+	for i, row := range rows {
+		if !reflect.DeepEqual(row.Feedback, want[i]) {
+			t.Errorf("row %d (%s): Feedback %v, want %v", i, row.Word, row.Feedback, want[i])
+		}
+	}
+	if last := rows[2]; last.Left != 0 || last.LeftAnswers != 0 {
+		t.Errorf("solved row: Left %d, LeftAnswers %d, want 0", last.Left, last.LeftAnswers)
+	}
+
+	rows = e.Analyze([]string{"aabba"}, "barva")
+	want = [][]Tile{tiles("aabba", "grey", "green", "orange", "grey", "green")}
+	if !reflect.DeepEqual(rows[0].Feedback, want[0]) {
+		t.Errorf("aabba: Feedback %v, want %v", rows[0].Feedback, want[0])
+	}
+}
+
+// TestRowWord: Row.Word je tip velkými písmeny bez diakritiky, i když je
+// řádek chybný.
+func TestRowWord(t *testing.T) {
+	e := testEngine(t)
+	tests := []struct {
+		guesses  []string
+		solution string
+		want     []string
+		errs     []bool
+	}{
+		{[]string{"kráva", "KRA\u0301VA", "krav", "kräva"}, "barva",
+			[]string{"KRAVA", "KRAVA", "KRAV", "KRÄVA"}, []bool{false, false, true, true}},
+		// chybné řešení: chybné jsou všechny řádky
+		{[]string{"kráva", "mleko"}, "barv", []string{"KRAVA", "MLEKO"}, []bool{true, true}},
+	}
+	for _, tt := range tests {
+		rows := e.Analyze(tt.guesses, tt.solution)
+		if len(rows) != len(tt.want) {
+			t.Errorf("Analyze(%q, %q): %d rows, want %d", tt.guesses, tt.solution, len(rows), len(tt.want))
+			continue
+		}
+		for i, row := range rows {
+			if row.Word != tt.want[i] || (row.Error != "") != tt.errs[i] {
+				t.Errorf("Analyze(%q, %q): row %d = %q, error %q", tt.guesses, tt.solution, i, row.Word, row.Error)
+			}
+		}
+	}
+}
+
+// TestEngineFromCompiled: engine ze zkompilovaného slovníku počítá stejně
+// jako z textového se stejnými answers (příznaky z kompilace se neuplatní).
+func TestEngineFromCompiled(t *testing.T) {
+	answers := []string{"krava", "barva", "vrana"}
+	d, err := dict.LoadDictionaryFromReader(strings.NewReader(testDict), dict.NewHistory([]dict.HistoryEntry{{Word: "mleko"}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.SetAnswers([]string{"pivko"})
+	data, err := dict.Compile(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compiled, err := NewEngineFromCompiled(data, nil, answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := testEngine(t, answers...)
+	if got, want := compiled.Dictionary().AnswerCount(), e.Dictionary().AnswerCount(); got != want {
+		t.Errorf("AnswerCount = %d, want %d", got, want)
+	}
+	if compiled.Dictionary().History().Len() != 0 {
+		t.Errorf("compiled history: %d entries, want 0", compiled.Dictionary().History().Len())
+	}
+	guesses := []string{"mleko", "barva", "krava"}
+	if got, want := compiled.Analyze(guesses, "kráva"), e.Analyze(guesses, "kráva"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Analyze: %+v, want %+v", got, want)
+	}
+
+	if _, err := NewEngineFromCompiled(data[:len(data)/2], nil, answers); err == nil {
+		t.Errorf("NewEngineFromCompiled with truncated data: want error")
+	}
+}
@@ -0,0 +1,54 @@
+// Compile převede textový slovník (s historií a seznamem odpovědí) do binárního formátu pro
+// dict.LoadCompiled, např. pro balíček embedded.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/pracj3am/wordle-solver/dict"
+)
+
+func main() {
+	dictPath := flag.String("dict", "db-hacky.txt", "textový slovník (slovo na řádek)")
+	historyPath := flag.String("history", "used.txt", "historie denních slov (Historic); prázdné = žádná")
+	answersPath := flag.String("answers", "", "seznam možných odpovědí; prázdné = všechna slova")
+	outPath := flag.String("o", "db-hacky.bin", "výstupní soubor")
+	flag.Parse()
+
+	var history *dict.History
+	if *historyPath != "" {
+		var err error
+		history, err = dict.LoadHistory(*historyPath)
+		if err != nil {
+			fmt.Println("loading history failed", err)
+			os.Exit(1)
+		}
+	}
+
+	words, err := dict.LoadDictionary(*dictPath, history)
+	if err != nil {
+		fmt.Println("loading words failed", err)
+		os.Exit(1)
+	}
+	if *answersPath != "" {
+		answers, err := dict.LoadAnswers(*answersPath)
+		if err != nil {
+			fmt.Println("loading answers failed", err)
+			os.Exit(1)
+		}
+		words.SetAnswers(answers)
+	}
+
+	data, err := dict.Compile(words)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*outPath, data, 0o644); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Printf("%s: %d words, %d bytes\n", *outPath, words.Len(), len(data))
+}
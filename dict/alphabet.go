@@ -0,0 +1,110 @@
+package dict
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Alphabet = písmena, ze kterých jsou slova slovníku, a jejich základní tvary.
+// Tabulky jsou odvozené v NewAlphabet: základní písmena mají indexy
+// 0..Bases-1 (v zadaném pořadí), za nimi následují varianty s diakritikou.
+type Alphabet struct {
+	Letters   []rune       // index → základní písmeno
+	Písmena   []rune       // index → písmeno (s diakritikou)
+	Indexes   map[rune]int // písmeno → index
+	Conv      map[rune]rune
+	ConvIndex []int // index → index základního písmene
+	Bases     int   // počet základních písmen
+}
+
+// NewAlphabet sestaví abecedu ze základních písmen bases a skupin variant;
+// skupina začíná základním písmenem, za ním jsou jeho varianty, např.
+// NewAlphabet("abc…z", "aá", "uúů").
+func NewAlphabet(bases string, variants ...string) (*Alphabet, error) {
+	a := &Alphabet{Indexes: make(map[rune]int), Conv: make(map[rune]rune)}
+	add := func(písmeno, letter rune) error {
+		if _, ok := a.Indexes[písmeno]; ok {
+			return fmt.Errorf("alphabet: duplicate letter %q", písmeno)
+		}
+		a.Indexes[písmeno] = len(a.Písmena)
+		a.Písmena = append(a.Písmena, písmeno)
+		a.Letters = append(a.Letters, letter)
+		a.Conv[písmeno] = letter
+		return nil
+	}
+
+	for _, letter := range bases {
+		if err := add(letter, letter); err != nil {
+			return nil, err
+		}
+	}
+	a.Bases = len(a.Písmena)
+	if a.Bases == 0 {
+		return nil, fmt.Errorf("alphabet: no letters")
+	}
+
+	for _, g := range variants {
+		group := []rune(g)
+		if len(group) < 2 {
+			return nil, fmt.Errorf("alphabet: %q: want a base letter and its variants", g)
+		}
+		letter := group[0]
+		if index, ok := a.Indexes[letter]; !ok || index >= a.Bases {
+			return nil, fmt.Errorf("alphabet: %q: %q is not a base letter", g, letter)
+		}
+		for _, písmeno := range group[1:] {
+			if err := add(písmeno, letter); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	a.ConvIndex = make([]int, len(a.Písmena))
+	for index, letter := range a.Letters {
+		a.ConvIndex[index] = a.Indexes[letter]
+	}
+	return a, nil
+}
+
+// MustAlphabet je NewAlphabet pro abecedy zadané v kódu; při chybě panikaří.
+func MustAlphabet(bases string, variants ...string) *Alphabet {
+	a, err := NewAlphabet(bases, variants...)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// Czech = česká abeceda (26 základních písmen a 15 s diakritikou), výchozí
+// pro slovník i progress.
+var Czech = MustAlphabet("abcdefghijklmnopqrstuvwxyz",
+	"aá", "cč", "dď", "eéě", "ií", "nň", "oó", "rř", "sš", "tť", "uúů", "yý", "zž")
+
+// Size = počet písmen abecedy (šířka uzlu trie).
+func (a *Alphabet) Size() int {
+	return len(a.Písmena)
+}
+
+// Index vrátí index písmena, nebo false, když v abecedě není.
+func (a *Alphabet) Index(písmeno rune) (int, bool) {
+	index, ok := a.Indexes[písmeno]
+	return index, ok
+}
+
+// Strip odstraní ze slova (po Canonical, tj. malými písmeny a NFC)
+// diakritiku. Znaky mimo abecedu ve výsledku nechá a první z nich vrátí jako
+// *LetterError.
+func (a *Alphabet) Strip(w string) (string, error) {
+	w = Canonical(w)
+	var err error
+	stripped := strings.Map(func(r rune) rune {
+		if letter, ok := a.Conv[r]; ok {
+			return letter
+		}
+		if err == nil {
+			err = &LetterError{w, r}
+		}
+		return r
+	}, w)
+	return stripped, err
+}
@@ -0,0 +1,63 @@
+package dict
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNewAlphabet(t *testing.T) {
+	a, err := NewAlphabet("abcz", "aáä", "zž")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(a.Písmena); got != "abczáäž" {
+		t.Errorf("Písmena = %q", got)
+	}
+	if got := string(a.Letters); got != "abczaaz" {
+		t.Errorf("Letters = %q", got)
+	}
+	if want := []int{0, 1, 2, 3, 0, 0, 3}; !slices.Equal(a.ConvIndex, want) {
+		t.Errorf("ConvIndex = %v, want %v", a.ConvIndex, want)
+	}
+	if a.Bases != 4 || a.Size() != 7 {
+		t.Errorf("Bases %d, Size %d, want 4, 7", a.Bases, a.Size())
+	}
+	if index, ok := a.Index('ä'); !ok || index != 5 {
+		t.Errorf("Index('ä') = %d, %v", index, ok)
+	}
+	if _, ok := a.Index('x'); ok {
+		t.Errorf("Index('x'): want false")
+	}
+
+	for _, tt := range []struct {
+		bases    string
+		variants []string
+	}{
+		{"", nil},
+		{"aba", nil},
+		{"ab", []string{"a"}},
+		{"ab", []string{"xá"}},
+		{"ab", []string{"aá", "bá"}},
+		{"ab", []string{"aáa"}},
+		{"ab", []string{"áa"}},
+	} {
+		if _, err := NewAlphabet(tt.bases, tt.variants...); err == nil {
+			t.Errorf("NewAlphabet(%q, %q): want error", tt.bases, tt.variants)
+		}
+	}
+}
+
+func TestAlphabetDictionary(t *testing.T) {
+	a := MustAlphabet("abcdefghijklmnopqrstuvwxyz", "aäå", "oö")
+	d, err := Loader{Alphabet: a}.LoadFromReader(strings.NewReader("bål\nbäl\nöga\nbal\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := d.LookupBase("bal"); len(got) != 3 {
+		t.Errorf("LookupBase(bal) = %d words, want 3", len(got))
+	}
+	if _, err := (Loader{Alphabet: a}).LoadFromReader(strings.NewReader("bál\n"), nil); err == nil {
+		t.Errorf("letter outside the alphabet: want error")
+	}
+}
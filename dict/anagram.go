@@ -0,0 +1,76 @@
+package dict
+
+import "slices"
+
+// AnagramIndex = slova slovníku seskupená podle písmen (přesmyčky), buď
+// přesně včetně diakritiky, nebo bez ní (fold). Je to snímek: pozdější Add
+// a Remove se v něm neprojeví.
+type AnagramIndex struct {
+	alphabet *Alphabet
+	fold     bool
+	groups   map[string][]*DictionaryWord // seřazená písmena → slova
+}
+
+// AnagramOptions = volby pro AnagramIndex.Find.
+type AnagramOptions struct {
+	Answers bool // jen možné odpovědi (Possible)
+	Moved   bool // žádné písmeno nezůstane na své pozici
+}
+
+// Anagrams sestaví index přesmyček; fold = písmena se porovnávají bez
+// diakritiky.
+func (d *Dictionary) Anagrams(fold bool) *AnagramIndex {
+	x := &AnagramIndex{alphabet: d.Alphabet, fold: fold, groups: make(map[string][]*DictionaryWord)}
+	for dw := range d.All() {
+		key := x.key(dw.Word)
+		x.groups[key] = append(x.groups[key], dw)
+	}
+	return x
+}
+
+// form = slovo tak, jak se v indexu porovnává (po Canonical). Znak mimo
+// abecedu v něm zůstane, takové slovo proto žádné přesmyčky nemá.
+func (x *AnagramIndex) form(word string) []rune {
+	word = Canonical(word)
+	if x.fold {
+		word, _ = x.alphabet.Strip(word)
+	}
+	return []rune(word)
+}
+
+func (x *AnagramIndex) key(word string) string {
+	letters := x.form(word)
+	slices.Sort(letters)
+	return string(letters)
+}
+
+// Find vrátí přesmyčky slova word (bez něj samotného a jeho variant, které
+// se v indexu neliší) v pořadí trie.
+func (x *AnagramIndex) Find(word string, opts AnagramOptions) []*DictionaryWord {
+	form := x.form(word)
+	var words []*DictionaryWord
+	for _, dw := range x.groups[x.key(word)] {
+		if opts.Answers && !dw.Possible() {
+			continue
+		}
+		other := x.form(dw.Word)
+		if slices.Equal(other, form) {
+			continue
+		}
+		if opts.Moved && samePosition(form, other) {
+			continue
+		}
+		words = append(words, dw)
+	}
+	return words
+}
+
+// samePosition = některé písmeno je v a i b na stejné pozici.
+func samePosition(a, b []rune) bool {
+	for i := range min(len(a), len(b)) {
+		if a[i] == b[i] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package dict
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAnagrams(t *testing.T) {
+	d, err := LoadDictionaryFromReader(strings.NewReader("kosti\nkostí\ntikos\nstiko\nkotis\nmleko\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exact, fold := d.Anagrams(false), d.Anagrams(true)
+	d.SetAnswers([]string{"kosti", "tikos"})
+	answers := d.Anagrams(false)
+	// index je snímek, pozdější Add se v něm neprojeví
+	if err := d.Add("sotik", true); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		x    *AnagramIndex
+		word string
+		opts AnagramOptions
+		want []string // seřazená
+	}{
+		{exact, "kosti", AnagramOptions{}, []string{"kotis", "stiko", "tikos"}},
+		{exact, "kosti", AnagramOptions{Moved: true}, []string{"stiko", "tikos"}},
+		{exact, "kostí", AnagramOptions{}, nil},
+		{exact, "mleko", AnagramOptions{}, nil},
+		{exact, "tisko", AnagramOptions{}, []string{"kosti", "kotis", "stiko", "tikos"}},
+		{fold, "kostí", AnagramOptions{}, []string{"kotis", "stiko", "tikos"}},
+		{fold, "tikos", AnagramOptions{}, []string{"kosti", "kostí", "kotis", "stiko"}},
+		{answers, "kosti", AnagramOptions{Answers: true}, []string{"tikos"}},
+		{answers, "kosti", AnagramOptions{}, []string{"kotis", "stiko", "tikos"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, dw := range tt.x.Find(tt.word, tt.opts) {
+			got = append(got, dw.Word)
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Find(%q, %+v), fold %v = %q, want %q", tt.word, tt.opts, tt.x.fold, got, tt.want)
+		}
+	}
+}
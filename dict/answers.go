@@ -0,0 +1,58 @@
+package dict
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+)
+
+// LoadAnswers načte seznam možných odpovědí (slovo na řádek, jen první
+// sloupec, převedený na Canonical) pro SetAnswers.
+func LoadAnswers(filePath string) ([]string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadAnswersFromReader(f)
+}
+
+// LoadAnswersFromReader je verze LoadAnswers čtoucí z io.Reader.
+func LoadAnswersFromReader(r io.Reader) ([]string, error) {
+	var answers []string
+
+	s := bufio.NewScanner(r)
+
+	for s.Scan() {
+		if fields := strings.Fields(s.Text()); len(fields) > 0 {
+			answers = append(answers, Canonical(strings.TrimPrefix(fields[0], "\uFEFF")))
+		}
+	}
+
+	return answers, s.Err()
+}
+
+// SetAnswers nastaví, která slova jsou odpovědi: ta, jejichž tvar bez
+// diakritiky je mezi answers (seznamy odpovědí bývají bez diakritiky).
+// Ostatní slova zůstanou jen platnými tipy; prázdný seznam = žádné odpovědi.
+func (d *Dictionary) SetAnswers(answers []string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	set := make(map[string]bool, len(answers))
+	for _, a := range answers {
+		// slovo s písmenem mimo abecedu ve slovníku není, přeskočí se
+		if base, err := d.Alphabet.Strip(a); err == nil {
+			set[base] = true
+		}
+	}
+	for _, w := range d.order {
+		node := d.node(w)
+		if answer := set[node.Word.WithoutDiacritics]; answer != node.Word.Answer {
+			c := *node.Word
+			c.Answer = answer
+			d.replace(node, &c)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package dict
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSetAnswers(t *testing.T) {
+	answers, err := LoadAnswersFromReader(strings.NewReader("\uFEFFKRAVA 12\n\nmleko\nkr1va\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"krava", "mleko", "kr1va"}; !slices.Equal(answers, want) {
+		t.Errorf("LoadAnswers = %q, want %q", answers, want)
+	}
+
+	d := queryDictionary(t)
+	tests := []struct {
+		answers []string
+		want    []string // odpovědi v pořadí trie
+	}{
+		// odpověď bez diakritiky platí pro všechny varianty
+		{answers, []string{"krava", "kráva", "mleko"}},
+		{[]string{"barva"}, []string{"barva"}},
+		{[]string{"BÁRVA", "pivko"}, []string{"barva"}},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		d.SetAnswers(tt.answers)
+		var got []string
+		for dw := range d.All() {
+			if dw.Answer {
+				got = append(got, dw.Word)
+			}
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("SetAnswers(%q): answers %q, want %q", tt.answers, got, tt.want)
+		}
+		if n := d.AnswerCount(); n != len(tt.want) {
+			t.Errorf("SetAnswers(%q): AnswerCount %d, want %d", tt.answers, n, len(tt.want))
+		}
+	}
+}
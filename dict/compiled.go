@@ -0,0 +1,213 @@
+package dict
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"slices"
+)
+
+// compiledMagic a compiledVersion = hlavička zkompilovaného slovníku.
+const (
+	compiledMagic   = "WSDC"
+	compiledVersion = 1
+)
+
+// Compile zapíše slovník v binárním formátu pro LoadCompiled:
+//
+//	"WSDC", verze (1 B), CRC-32 (IEEE) zbytku (4 B, little endian),
+//	abeceda (základní písmena a skupiny variant v pořadí indexů),
+//	délka slov, počet slov, slova (index písmene na 1 B), bitmapy Answer
+//	a Historic (1 bit na slovo) a příznak vah (1 B), je-li 1, váhy (float64 na slovo).
+//
+// Slova jsou v pořadí načtení (jako WriteTo). Z historie zůstanou jen
+// příznaky Historic (bez dat).
+func Compile(d *Dictionary) ([]byte, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	a := d.Alphabet
+	if a.Size() > 256 {
+		return nil, fmt.Errorf("dict: %d letters do not fit a byte", a.Size())
+	}
+	var b []byte
+	appendString := func(s string) {
+		b = binary.AppendUvarint(b, uint64(len(s)))
+		b = append(b, s...)
+	}
+	appendString(string(a.Písmena[:a.Bases]))
+	groups := variantGroups(a)
+	b = binary.AppendUvarint(b, uint64(len(groups)))
+	for _, g := range groups {
+		appendString(g)
+	}
+
+	b = binary.AppendUvarint(b, uint64(d.Size))
+	b = binary.AppendUvarint(b, uint64(len(d.order)))
+	answer := make([]byte, (len(d.order)+7)/8)
+	historic := make([]byte, len(answer))
+	weights := make([]float64, len(d.order))
+	weighted := false
+	for i, w := range d.order {
+		for _, písmeno := range w {
+			b = append(b, byte(a.Indexes[písmeno]))
+		}
+		dw := d.node(w).Word
+		if dw.Answer {
+			answer[i/8] |= 1 << (i % 8)
+		}
+		if dw.Historic {
+			historic[i/8] |= 1 << (i % 8)
+		}
+		weights[i] = dw.Weight
+		weighted = weighted || dw.Weight != 1
+	}
+	b = append(b, answer...)
+	b = append(b, historic...)
+	if weighted {
+		b = append(b, 1)
+		for _, weight := range weights {
+			b = binary.LittleEndian.AppendUint64(b, math.Float64bits(weight))
+		}
+	} else {
+		b = append(b, 0)
+	}
+
+	header := append([]byte(compiledMagic), compiledVersion)
+	header = binary.LittleEndian.AppendUint32(header, crc32.ChecksumIEEE(b))
+	return append(header, b...), nil
+}
+
+// variantGroups vrátí varianty abecedy jako skupiny pro NewAlphabet (základní
+// písmeno + po sobě jdoucí varianty), aby NewAlphabet dal písmenům stejné indexy.
+func variantGroups(a *Alphabet) []string {
+	var groups []string
+	for index := a.Bases; index < a.Size(); index++ {
+		if index == a.Bases || a.Letters[index] != a.Letters[index-1] {
+			groups = append(groups, string(a.Letters[index]))
+		}
+		groups[len(groups)-1] += string(a.Písmena[index])
+	}
+	return groups
+}
+
+var errCompiled = errors.New("dict: truncated or corrupt compiled dictionary")
+
+// LoadCompiled sestaví slovník z výstupu Compile (bez parsování textu).
+// Abeceda shodná s Czech se použije přímo (pravidla z Equivalence(Czech)
+// na slovník pasují).
+func LoadCompiled(data []byte) (*Dictionary, error) {
+	if len(data) < len(compiledMagic)+5 || string(data[:len(compiledMagic)]) != compiledMagic {
+		return nil, errCompiled
+	}
+	data = data[len(compiledMagic):]
+	version := data[0]
+	if version != compiledVersion {
+		return nil, fmt.Errorf("dict: unsupported compiled version %d", version)
+	}
+	sum := binary.LittleEndian.Uint32(data[1:])
+	data = data[5:]
+	if crc32.ChecksumIEEE(data) != sum {
+		return nil, errors.New("dict: compiled dictionary checksum mismatch")
+	}
+
+	uvarint := func() (int, bool) {
+		v, n := binary.Uvarint(data)
+		if n <= 0 || v > uint64(len(data)) {
+			return 0, false
+		}
+		data = data[n:]
+		return int(v), true
+	}
+	str := func() (string, bool) {
+		n, ok := uvarint()
+		if !ok || n > len(data) {
+			return "", false
+		}
+		s := string(data[:n])
+		data = data[n:]
+		return s, true
+	}
+
+	bases, ok := str()
+	if !ok {
+		return nil, errCompiled
+	}
+	n, ok := uvarint()
+	if !ok {
+		return nil, errCompiled
+	}
+	groups := make([]string, n)
+	for i := range groups {
+		if groups[i], ok = str(); !ok {
+			return nil, errCompiled
+		}
+	}
+	a, err := NewAlphabet(bases, groups...)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Equal(a.Písmena, Czech.Písmena) && slices.Equal(a.Letters, Czech.Letters) {
+		a = Czech
+	}
+
+	size, ok := uvarint()
+	if !ok {
+		return nil, errCompiled
+	}
+	count, ok := uvarint()
+	bitmap := (count + 7) / 8
+	if !ok || size == 0 && count > 0 || count*size+2*bitmap+1 > len(data) {
+		return nil, errCompiled
+	}
+	letters := data[:count*size]
+	data = data[len(letters):]
+	answer, historic := data[:bitmap], data[bitmap:2*bitmap]
+	data = data[2*bitmap:]
+	if data[0] > 1 {
+		return nil, errCompiled
+	}
+	var weights []byte
+	if data[0] == 1 {
+		weights = data[1:]
+		if len(weights) != 8*count {
+			return nil, errCompiled
+		}
+	}
+	if len(data) != 1+len(weights) {
+		return nil, errCompiled
+	}
+
+	d := NewDictionary(a)
+	d.Size = size
+	indexes := make([]int, size)
+	word := make([]rune, size)
+	var entries []HistoryEntry
+	for i := range count {
+		for j, index := range letters[i*size : (i+1)*size] {
+			if int(index) >= a.Size() {
+				return nil, errCompiled
+			}
+			indexes[j], word[j] = int(index), a.Písmena[index]
+		}
+		weight := 1.0
+		if weights != nil {
+			weight = math.Float64frombits(binary.LittleEndian.Uint64(weights[8*i:]))
+		}
+		bit := byte(1) << (i % 8)
+		isAnswer := answer[i/8]&bit != 0
+		isHistoric := historic[i/8]&bit != 0
+		if !d.insert(indexes, string(word), isAnswer, isHistoric, weight) {
+			return nil, fmt.Errorf("dict: compiled dictionary: duplicate word %q", string(word))
+		}
+		if isHistoric {
+			entries = append(entries, HistoryEntry{Word: string(word)})
+		}
+	}
+	if entries != nil {
+		d.history = NewHistory(entries)
+	}
+	return d, nil
+}
@@ -0,0 +1,87 @@
+package dict
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompileRoundTrip(t *testing.T) {
+	history := NewHistory([]HistoryEntry{{Word: "barva"}, {Word: "vrána"}})
+	tests := []struct {
+		name    string
+		in      string
+		answers []string // nil = všechna slova jsou odpovědi
+	}{
+		{"plain", "krava\nbarva\nmleko\n", nil},
+		{"weights", "kráva 2.5\nkrava\nbarva 0\nvrána\n", nil},
+		{"answers", "kráva\nkrava\nbarva\nvrána\npivko\n", []string{"krava", "vrana"}},
+		{"empty", "", nil},
+	}
+	for _, tt := range tests {
+		d, err := LoadDictionaryFromReader(strings.NewReader(tt.in), history)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if tt.answers != nil {
+			d.SetAnswers(tt.answers)
+		}
+		data, err := Compile(d)
+		if err != nil {
+			t.Fatalf("%s: Compile: %v", tt.name, err)
+		}
+		c, err := LoadCompiled(data)
+		if err != nil {
+			t.Fatalf("%s: LoadCompiled: %v", tt.name, err)
+		}
+
+		if c.Size != d.Size || c.Len() != d.Len() || c.Alphabet.Size() != d.Alphabet.Size() {
+			t.Errorf("%s: size %d, len %d, alphabet %d; want %d, %d, %d", tt.name,
+				c.Size, c.Len(), c.Alphabet.Size(), d.Size, d.Len(), d.Alphabet.Size())
+		}
+		for _, w := range d.order {
+			want, _ := d.Lookup(w)
+			got, ok := c.Lookup(w)
+			if !ok {
+				t.Errorf("%s: %q missing", tt.name, w)
+				continue
+			}
+			if got.WithoutDiacritics != want.WithoutDiacritics || got.Answer != want.Answer ||
+				got.Historic != want.Historic || got.Weight != want.Weight || got.Unique() != want.Unique() {
+				t.Errorf("%s: %+v, want %+v", tt.name, *got, *want)
+			}
+			if c.History().Contains(w) != want.Historic {
+				t.Errorf("%s: History().Contains(%q) = %v, want %v", tt.name, w, !want.Historic, want.Historic)
+			}
+		}
+
+		var b1, b2 strings.Builder
+		d.WriteTo(&b1)
+		c.WriteTo(&b2)
+		if b1.String() != b2.String() {
+			t.Errorf("%s: WriteTo %q, want %q", tt.name, b2.String(), b1.String())
+		}
+	}
+}
+
+func TestLoadCompiledCorrupt(t *testing.T) {
+	d, err := LoadDictionaryFromReader(strings.NewReader("kráva 2.5\nkrava\nbarva\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := Compile(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n := range len(data) {
+		if _, err := LoadCompiled(data[:n]); err == nil {
+			t.Errorf("LoadCompiled(%x): want error", data[:n])
+		}
+	}
+	for i := range data {
+		bad := []byte(string(data))
+		bad[i] ^= 0x40
+		if _, err := LoadCompiled(bad); err == nil {
+			t.Errorf("LoadCompiled with byte %d changed: want error", i)
+		}
+	}
+}
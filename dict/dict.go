@@ -1,234 +1,177 @@
 package dict
 
-import (
-	"bufio"
-	"io"
-	"os"
-	"unicode/utf8"
-)
+import "sync"
 
-var Letters = [41]rune{
-	'a',
-	'b',
-	'c',
-	'd',
-	'e',
-	'f',
-	'g',
-	'h',
-	'i',
-	'j',
-	'k',
-	'l',
-	'm',
-	'n',
-	'o',
-	'p',
-	'q',
-	'r',
-	's',
-	't',
-	'u',
-	'v',
-	'w',
-	'x',
-	'y',
-	'z',
-	'a',
-	'c',
-	'd',
-	'e',
-	'e',
-	'i',
-	'n',
-	'o',
-	'r',
-	's',
-	't',
-	'u',
-	'u',
-	'y',
-	'z',
-}
-var Písmena = [41]rune{
-	'a',
-	'b',
-	'c',
-	'd',
-	'e',
-	'f',
-	'g',
-	'h',
-	'i',
-	'j',
-	'k',
-	'l',
-	'm',
-	'n',
-	'o',
-	'p',
-	'q',
-	'r',
-	's',
-	't',
-	'u',
-	'v',
-	'w',
-	'x',
-	'y',
-	'z',
-	'á',
-	'č',
-	'ď',
-	'é',
-	'ě',
-	'í',
-	'ň',
-	'ó',
-	'ř',
-	'š',
-	'ť',
-	'ú',
-	'ů',
-	'ý',
-	'ž',
-}
-var Indexes = map[rune]int{
-	'a': 0,
-	'b': 1,
-	'c': 2,
-	'd': 3,
-	'e': 4,
-	'f': 5,
-	'g': 6,
-	'h': 7,
-	'i': 8,
-	'j': 9,
-	'k': 10,
-	'l': 11,
-	'm': 12,
-	'n': 13,
-	'o': 14,
-	'p': 15,
-	'q': 16,
-	'r': 17,
-	's': 18,
-	't': 19,
-	'u': 20,
-	'v': 21,
-	'w': 22,
-	'x': 23,
-	'y': 24,
-	'z': 25,
-	'á': 26,
-	'č': 27,
-	'ď': 28,
-	'é': 29,
-	'ě': 30,
-	'í': 31,
-	'ň': 32,
-	'ó': 33,
-	'ř': 34,
-	'š': 35,
-	'ť': 36,
-	'ú': 37,
-	'ů': 38,
-	'ý': 39,
-	'ž': 40,
-}
+// Letters, Písmena a Indexes = tabulky české abecedy (viz Czech).
+var (
+	Letters = Czech.Letters
+	Písmena = Czech.Písmena
+	Indexes = Czech.Indexes
+)
 
+// DictionaryWord = slovo slovníku; každé je platný tip. Slovník vydané
+// DictionaryWord nemění (Add, Remove, MarkUsed, SetAnswers a SetHistory ho
+// nahradí kopií), takže se dá číst bez zámku.
 type DictionaryWord struct {
 	Word              string
 	WithoutDiacritics string
-	Used              bool
-}
+	Answer            bool    // je v seznamu odpovědí (viz SetAnswers; bez seznamu všechna slova)
+	Historic          bool    // už bylo denním slovem (je v History)
+	Weight            float64 // jak pravděpodobné je slovo jako řešení (2. sloupec slovníku, výchozí 1)
 
-type nextLetter struct {
-	Next [41]*nextLetter
-	Word *DictionaryWord
+	variants int // kolik slov slovníku má stejný WithoutDiacritics
 }
-type Dictionary struct {
-	First [41]*nextLetter
+
+// Possible = slovo může být řešením: je to odpověď, která ještě nebyla.
+func (dw *DictionaryWord) Possible() bool {
+	return dw.Answer && !dw.Historic
 }
 
-func LoadDictionary(filePath string, history map[string]bool) (*Dictionary, error) {
-	f, err := os.Open(filePath)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-	return LoadDictionaryFromReader(f, history)
+// Unique = ve slovníku není jiné slovo, které se liší jen diakritikou.
+func (dw *DictionaryWord) Unique() bool {
+	return dw.variants <= 1
 }
 
-// LoadDictionaryFromReader je verze LoadDictionary čtoucí z io.Reader (např. pro WASM,
-// kde není souborový systém — data se předají jako bytes).
-func LoadDictionaryFromReader(r io.Reader, history map[string]bool) (*Dictionary, error) {
-	var words Dictionary
+type nextLetter struct {
+	Next []*nextLetter // indexed by letter index (Alphabet)
+	Word *DictionaryWord
+}
 
-	s := bufio.NewScanner(r)
+// Dictionary = trie slov; všechna slova mají Size písmen z abecedy Alphabet.
+// Čtení (Walk, Lookup …) a změny (Add, Remove, MarkUsed, SetAnswers,
+// SetHistory) jsou bezpečné souběžně; First se čte bez zámku, jen když slovník nikdo nemění.
+type Dictionary struct {
+	First    []*nextLetter
+	Size     int
+	Alphabet *Alphabet
 
-	for s.Scan() {
-		word := s.Bytes()
+	Fixed []*LineError // co Loader.Lenient opravil (nebo vynechal)
 
-		i := 0
-		l1, t := utf8.DecodeRune(word[i:])
-		i += t
-		l2, t := utf8.DecodeRune(word[i:])
-		i += t
-		l3, t := utf8.DecodeRune(word[i:])
-		i += t
-		l4, t := utf8.DecodeRune(word[i:])
-		i += t
-		l5, t := utf8.DecodeRune(word[i:])
+	mu      sync.RWMutex
+	history *History                     // denní slova, která už byla (nil = žádná)
+	len     int                          // počet slov
+	order   []string                     // slova v pořadí načtení / přidání (pro WriteTo)
+	bases   map[string][]*DictionaryWord // WithoutDiacritics → varianty
+}
 
-		i1, i2, i3, i4, i5 := Indexes[l1], Indexes[l2], Indexes[l3], Indexes[l4], Indexes[l5]
+// NewDictionary vrátí prázdný slovník nad abecedou a (nil = Czech).
+func NewDictionary(a *Alphabet) *Dictionary {
+	if a == nil {
+		a = Czech
+	}
+	return &Dictionary{Alphabet: a, First: make([]*nextLetter, a.Size())}
+}
 
-		if words.First[i1] == nil {
-			words.First[i1] = new(nextLetter)
-		}
-		if words.First[i1].Next[i2] == nil {
-			words.First[i1].Next[i2] = new(nextLetter)
+// insert vloží slovo s indexy písmen indexes; false = už ve slovníku je.
+// Volající drží zámek (nebo slovník ještě nikomu nedal).
+func (d *Dictionary) insert(indexes []int, w string, answer, historic bool, weight float64) bool {
+	next := d.First
+	var node *nextLetter
+	for i, index := range indexes {
+		if next[index] == nil {
+			next[index] = new(nextLetter)
 		}
-		if words.First[i1].Next[i2].Next[i3] == nil {
-			words.First[i1].Next[i2].Next[i3] = new(nextLetter)
-		}
-		if words.First[i1].Next[i2].Next[i3].Next[i4] == nil {
-			words.First[i1].Next[i2].Next[i3].Next[i4] = new(nextLetter)
-		}
-		if words.First[i1].Next[i2].Next[i3].Next[i4].Next[i5] == nil {
-			lastLetter := new(nextLetter)
-			w := s.Text()
-			lastLetter.Word = &DictionaryWord{
-				Word:              w,
-				WithoutDiacritics: StripDiacritic(w),
-				Used:              history[w],
-			}
-			words.First[i1].Next[i2].Next[i3].Next[i4].Next[i5] = lastLetter
+		node = next[index]
+		if i+1 < len(indexes) && node.Next == nil {
+			node.Next = make([]*nextLetter, d.Alphabet.Size())
 		}
+		next = node.Next
+	}
+	if node == nil || node.Word != nil {
+		return false
 	}
 
-	return &words, s.Err()
+	// základní tvar podle indexů, písmena už jsou ověřená
+	base := make([]rune, len(indexes))
+	for i, index := range indexes {
+		base[i] = d.Alphabet.Letters[index]
+	}
+	dw := &DictionaryWord{
+		Word:              w,
+		WithoutDiacritics: string(base),
+		Answer:            answer,
+		Historic:          historic,
+		Weight:            weight,
+	}
+	node.Word = dw
+	d.len++
+	d.order = append(d.order, w)
+	d.setVariants(dw.WithoutDiacritics, append(d.bases[dw.WithoutDiacritics], dw))
+	return true
 }
 
-func LoadHistory(filePath string) (map[string]bool, error) {
-	f, err := os.Open(filePath)
-	if err != nil {
-		return nil, err
+// setVariants uloží varianty základního tvaru base; slovům, kterým se změnil
+// počet variant, dá v trie novou kopii (vydané DictionaryWord se nemění).
+func (d *Dictionary) setVariants(base string, variants []*DictionaryWord) {
+	if len(variants) == 0 {
+		delete(d.bases, base)
+		return
+	}
+	if d.bases == nil {
+		d.bases = make(map[string][]*DictionaryWord)
+	}
+	for i, v := range variants {
+		if v.variants == len(variants) {
+			continue
+		}
+		c := *v
+		c.variants = len(variants)
+		variants[i] = &c
+		d.node(c.Word).Word = &c
 	}
-	defer f.Close()
-	return LoadHistoryFromReader(f)
+	d.bases[base] = variants
 }
 
-// LoadHistoryFromReader je verze LoadHistory čtoucí z io.Reader (viz LoadDictionaryFromReader).
-func LoadHistoryFromReader(r io.Reader) (map[string]bool, error) {
-	words := make(map[string]bool)
-
-	s := bufio.NewScanner(r)
+// node vrátí uzel trie, ve kterém končí slovo w (nil, když tam žádná cesta
+// nevede).
+func (d *Dictionary) node(w string) *nextLetter {
+	next := d.First
+	var node *nextLetter
+	for _, písmeno := range w {
+		index, ok := d.Alphabet.Index(písmeno)
+		if !ok || next == nil || next[index] == nil {
+			return nil
+		}
+		node = next[index]
+		next = node.Next
+	}
+	return node
+}
 
-	for s.Scan() {
-		word := s.Text()
-		words[word] = true
+// Walk projde trie do hloubky. Do větve s písmenem index na pozici pos vstoupí,
+// jen když prefix(pos, index) vrátí true; visit dostane indexy písmen slova
+// (platné jen během volání) a vrácením false procházení ukončí. Walk drží
+// zámek pro čtení, prefix ani visit proto slovník volat nesmí (totéž platí
+// pro tělo cyklu přes All a Progress.Words).
+func (d *Dictionary) Walk(prefix func(pos, index int) bool, visit func(indexes []int, dw *DictionaryWord) bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.Size == 0 {
+		return
 	}
+	indexes := make([]int, d.Size)
+	walk(d.First, 0, indexes, prefix, visit)
+}
 
-	return words, s.Err()
+func walk(
+	next []*nextLetter,
+	pos int,
+	indexes []int,
+	prefix func(pos, index int) bool,
+	visit func(indexes []int, dw *DictionaryWord) bool,
+) bool {
+	for index, node := range next {
+		if node == nil || !prefix(pos, index) {
+			continue
+		}
+		indexes[pos] = index
+		if pos+1 == len(indexes) {
+			if node.Word != nil && !visit(indexes, node.Word) {
+				return false
+			}
+		} else if !walk(node.Next, pos+1, indexes, prefix, visit) {
+			return false
+		}
+	}
+	return true
 }
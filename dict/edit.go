@@ -0,0 +1,157 @@
+package dict
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Add přidá slovo (převedené na Canonical) do slovníku s Weight = 1;
+// answer určí, zda je odpovědí (SetAnswers si slovník nepamatuje), Historic
+// se nastaví podle History. Slovo musí mít Size písmen z abecedy; do
+// prázdného slovníku první slovo Size určí.
+func (d *Dictionary) Add(word string, answer bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	word = Canonical(word)
+	indexes := make([]int, 0, d.Size)
+	for _, písmeno := range word {
+		index, ok := d.Alphabet.Index(písmeno)
+		if !ok {
+			return fmt.Errorf("dict: %q: unknown letter %q", word, písmeno)
+		}
+		indexes = append(indexes, index)
+	}
+	switch {
+	case len(indexes) == 0:
+		return fmt.Errorf("dict: empty word")
+	case d.Size == 0:
+		d.Size = len(indexes)
+	case len(indexes) != d.Size:
+		return fmt.Errorf("dict: %q has %d letters, want %d", word, len(indexes), d.Size)
+	}
+	if !d.insert(indexes, word, answer, d.history.Contains(word), 1) {
+		return fmt.Errorf("dict: %q is already in the dictionary", word)
+	}
+	return nil
+}
+
+// Remove odebere slovo (převedené na Canonical) ze slovníku.
+func (d *Dictionary) Remove(word string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	word = Canonical(word)
+	node := d.node(word)
+	if node == nil || node.Word == nil {
+		return fmt.Errorf("dict: %q is not in the dictionary", word)
+	}
+	base := node.Word.WithoutDiacritics
+	node.Word = nil
+	d.len--
+	d.order = slices.DeleteFunc(d.order, func(w string) bool { return w == word })
+	d.setVariants(base, slices.DeleteFunc(slices.Clone(d.bases[base]), func(v *DictionaryWord) bool {
+		return v.Word == word
+	}))
+	return nil
+}
+
+// MarkUsed připíše slovo (převedené na Canonical) do historie jako denní slovo dne date (nulové =
+// neznámý den) a označí ho Historic (už nemůže být řešením). Vrátí novou
+// položku historie (s dopočítaným číslem hádanky, viz NewHistory), např.
+// pro AppendHistory.
+func (d *Dictionary) MarkUsed(word string, date time.Time) (HistoryEntry, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	word = Canonical(word)
+	node := d.node(word)
+	if node == nil || node.Word == nil {
+		return HistoryEntry{}, fmt.Errorf("dict: %q is not in the dictionary", word)
+	}
+	if node.Word.Historic {
+		return HistoryEntry{}, fmt.Errorf("dict: %q is already in the history", word)
+	}
+	d.history = d.history.with(HistoryEntry{Word: word, Date: date})
+	c := *node.Word
+	c.Historic = true
+	d.replace(node, &c)
+	return d.history.Entries[len(d.history.Entries)-1], nil
+}
+
+// replace nahradí slovo v uzlu node kopií c (i mezi variantami). Volající
+// drží zámek.
+func (d *Dictionary) replace(node *nextLetter, c *DictionaryWord) {
+	variants := slices.Clone(d.bases[c.WithoutDiacritics])
+	variants[slices.Index(variants, node.Word)] = c
+	node.Word = c
+	d.bases[c.WithoutDiacritics] = variants
+}
+
+// WriteTo zapíše slova slovníku po řádcích ve formátu pro LoadDictionary
+// (v pořadí načtení, přidaná na konci; váhu jen, když není 1).
+func (d *Dictionary) WriteTo(w io.Writer) (int64, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	bw := bufio.NewWriter(w)
+	var n int64
+	for _, word := range d.order {
+		if weight := d.node(word).Word.Weight; weight != 1 {
+			word += "\t" + strconv.FormatFloat(weight, 'g', -1, 64)
+		}
+		m, err := bw.WriteString(word + "\n")
+		n += int64(m)
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, bw.Flush()
+}
+
+// Save přepíše soubor filePath slovy slovníku (viz WriteTo); zapisuje do
+// dočasného souboru a ten přejmenuje, takže soubor není nikdy napůl zapsaný.
+func (d *Dictionary) Save(filePath string) error {
+	f, err := os.CreateTemp(filepath.Dir(filePath), filepath.Base(filePath)+".*")
+	if err != nil {
+		return err
+	}
+	mode := os.FileMode(0o644)
+	if fi, err := os.Stat(filePath); err == nil {
+		mode = fi.Mode().Perm()
+	}
+	if err := f.Chmod(mode); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if _, err := d.WriteTo(f); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), filePath)
+}
+
+// AppendHistory připíše položku na konec souboru historie (viz LoadHistory).
+func AppendHistory(filePath string, e HistoryEntry) error {
+	f, err := os.OpenFile(filePath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(f, e); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,116 @@
+package dict
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEdit(t *testing.T) {
+	d, err := LoadDictionaryFromReader(strings.NewReader("krava\nbarva\t2\nmleko\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Add("kráva", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Add("pivko", true); err != nil {
+		t.Fatal(err)
+	}
+	// chyby: už je ve slovníku, špatná délka, písmeno mimo abecedu
+	for _, word := range []string{"krava", "krav", "krav1"} {
+		if err := d.Add(word, true); err == nil {
+			t.Errorf("Add(%q): want error", word)
+		}
+	}
+	if dw, _ := d.Lookup("kráva"); dw.Possible() || dw.Weight != 1 {
+		t.Errorf("Add(%q, false): Possible %v, Weight %v", "kráva", dw.Possible(), dw.Weight)
+	}
+	if err := d.Remove("mleko"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Remove("mleko"); err == nil {
+		t.Errorf("Remove(%q) twice: want error", "mleko")
+	}
+
+	used := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	e, err := d.MarkUsed("krava", used)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Word != "krava" || !e.Date.Equal(used) {
+		t.Errorf("MarkUsed: entry %v", e)
+	}
+	if _, err := d.MarkUsed("krava", used); err == nil {
+		t.Errorf("MarkUsed(%q) twice: want error", "krava")
+	}
+	if dw, _ := d.Lookup("krava"); !dw.Historic || dw.Possible() {
+		t.Errorf("MarkUsed(%q): Historic %v, Possible %v", "krava", dw.Historic, dw.Possible())
+	}
+	if !d.History().Contains("krava") {
+		t.Errorf("MarkUsed(%q): not in the history", "krava")
+	}
+	if d.Len() != 4 || d.AnswerCount() != 2 {
+		t.Errorf("Len, AnswerCount = %d, %d, want 4, 2", d.Len(), d.AnswerCount())
+	}
+	var base []string
+	for _, dw := range d.LookupBase("krava") {
+		base = append(base, dw.Word)
+	}
+	if !slices.Equal(base, []string{"krava", "kráva"}) {
+		t.Errorf("LookupBase(%q) = %q", "krava", base)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "words.txt")
+	if err := d.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "krava\nbarva\t2\nkráva\npivko\n"; string(b) != want {
+		t.Errorf("Save: %q, want %q", b, want)
+	}
+
+	history := filepath.Join(dir, "used.txt")
+	for _, e := range []HistoryEntry{{Word: "barva", Day: 3}, e} {
+		if err := AppendHistory(history, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b, err = os.ReadFile(history)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "3 barva\n2024-01-02 krava\n"; string(b) != want {
+		t.Errorf("AppendHistory: %q, want %q", b, want)
+	}
+}
+
+// TestAllStopsEarly: All vydává slova přímo z průchodu trie, break ho
+// ukončí a zámek se uvolní.
+func TestAllStopsEarly(t *testing.T) {
+	d, err := LoadDictionaryFromReader(strings.NewReader("krava\nbarva\nmleko\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for range d.All() {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("All: %d words before break, want 1", n)
+	}
+	if err := d.Add("pivko", true); err != nil {
+		t.Fatal(err)
+	}
+	if words := slices.Collect(d.All()); len(words) != 4 {
+		t.Errorf("All: %d words, want 4", len(words))
+	}
+}
@@ -0,0 +1,307 @@
+package dict
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayout = formát data v souboru historie.
+const dateLayout = "2006-01-02"
+
+// HistoryEntry = jedno denní slovo historie.
+type HistoryEntry struct {
+	Word string
+	Date time.Time // den (půlnoc UTC); nulový = neznámý
+	Day  int       // číslo hádanky; 0 = neznámé
+}
+
+// String vrátí položku jako řádek souboru historie ("[číslo] [datum] slovo").
+func (e HistoryEntry) String() string {
+	var fields []string
+	if e.Day != 0 {
+		fields = append(fields, strconv.Itoa(e.Day))
+	}
+	if !e.Date.IsZero() {
+		fields = append(fields, e.Date.Format(dateLayout))
+	}
+	return strings.Join(append(fields, e.Word), " ")
+}
+
+// History = denní slova, která už byla (used.txt), v pořadí souboru (tj.
+// chronologicky). Po načtení se nemění (MarkUsed, Before … vytvoří novou).
+type History struct {
+	Entries []HistoryEntry
+	words   map[string]bool
+	epoch   time.Time // datum hádanky číslo 0; nulové = neznámé
+}
+
+// NewHistory sestaví historii z položek (slova převede na Canonical). Má-li
+// některá položka číslo i datum, dopočítá z nich ostatním chybějící datum
+// nebo číslo.
+func NewHistory(entries []HistoryEntry) *History {
+	h := &History{Entries: make([]HistoryEntry, len(entries)), words: make(map[string]bool, len(entries))}
+	for _, e := range entries {
+		if e.Day != 0 && !e.Date.IsZero() {
+			h.epoch = day(e.Date).AddDate(0, 0, -e.Day)
+			break
+		}
+	}
+	for i, e := range entries {
+		e.Word = Canonical(e.Word)
+		if !e.Date.IsZero() {
+			e.Date = day(e.Date)
+		}
+		if !h.epoch.IsZero() {
+			if e.Date.IsZero() && e.Day != 0 {
+				e.Date = h.epoch.AddDate(0, 0, e.Day)
+			} else if e.Day == 0 && !e.Date.IsZero() {
+				e.Day = days(h.epoch, e.Date)
+			}
+		}
+		h.Entries[i] = e
+		h.words[e.Word] = true
+	}
+	return h
+}
+
+// day vrátí den t (podle jeho časové zóny) jako půlnoc UTC.
+func day(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// days = počet dní od from do to (obojí půlnoc UTC).
+func days(from, to time.Time) int {
+	return int(to.Sub(from).Hours()) / 24
+}
+
+// Contains = slovo (přesně, včetně diakritiky) už bylo denním slovem; nil
+// historie neobsahuje nic.
+func (h *History) Contains(word string) bool {
+	return h != nil && h.words[word]
+}
+
+// Len = počet položek historie.
+func (h *History) Len() int {
+	if h == nil {
+		return 0
+	}
+	return len(h.Entries)
+}
+
+// On vrátí denní slovo ze dne date.
+func (h *History) On(date time.Time) (HistoryEntry, bool) {
+	date = day(date)
+	for _, e := range h.entries() {
+		if e.Date.Equal(date) {
+			return e, true
+		}
+	}
+	return HistoryEntry{}, false
+}
+
+// OnDay vrátí denní slovo hádanky číslo n.
+func (h *History) OnDay(n int) (HistoryEntry, bool) {
+	for _, e := range h.entries() {
+		if e.Day == n {
+			return e, true
+		}
+	}
+	return HistoryEntry{}, false
+}
+
+// Before vrátí historii tak, jak byla ráno dne date (bez slova toho dne),
+// např. pro LoadDictionary nebo SetHistory při rozboru staré hry. Položky
+// bez data se berou podle pořadí v souboru: patří do ní, jsou-li před
+// položkou ze dne date nebo dřívějšího.
+func (h *History) Before(date time.Time) *History {
+	date = day(date)
+	return h.before(func(e HistoryEntry) (bool, int) {
+		return !e.Date.IsZero(), e.Date.Compare(date)
+	})
+}
+
+// BeforeDay je Before pro číslo hádanky.
+func (h *History) BeforeDay(n int) *History {
+	return h.before(func(e HistoryEntry) (bool, int) {
+		return e.Day != 0, cmp.Compare(e.Day, n)
+	})
+}
+
+// before vrátí položky, které jsou známě (known) dřív než hranice (order < 0),
+// a položky bez data před poslední položkou z hranice nebo dřívější.
+func (h *History) before(order func(HistoryEntry) (known bool, order int)) *History {
+	entries := h.entries()
+	last := 0
+	for i, e := range entries {
+		if known, o := order(e); known && o <= 0 {
+			last = i
+		}
+	}
+	var kept []HistoryEntry
+	for _, e := range entries[:last] {
+		if known, o := order(e); !known || o < 0 {
+			kept = append(kept, e)
+		}
+	}
+	for _, e := range entries[last:] {
+		if known, o := order(e); known && o < 0 {
+			kept = append(kept, e)
+		}
+	}
+	return NewHistory(kept)
+}
+
+func (h *History) entries() []HistoryEntry {
+	if h == nil {
+		return nil
+	}
+	return h.Entries
+}
+
+// with vrátí kopii historie s další položkou.
+func (h *History) with(e HistoryEntry) *History {
+	entries := h.entries()
+	return NewHistory(append(entries[:len(entries):len(entries)], e))
+}
+
+func LoadHistory(filePath string) (*History, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadHistoryFromReader(f)
+}
+
+// LoadHistoryFromReader je verze LoadHistory čtoucí z io.Reader (viz LoadDictionaryFromReader).
+// Řádek je "[číslo hádanky] [datum 2006-01-02] slovo" (slovo se převede na
+// Canonical, prázdné řádky se přeskočí). Opakované slovo, datum nebo číslo
+// a číslo nesedící k datu jsou chyby (*LoadError).
+func LoadHistoryFromReader(r io.Reader) (*History, error) {
+	var entries []HistoryEntry
+	var errs []*LineError
+	words := make(map[string]int) // slovo → řádek
+	dates := make(map[time.Time]int)
+	numbers := make(map[int]int)
+	var epoch time.Time
+	epochLine := 0
+
+	s := bufio.NewScanner(r)
+	line := 0
+
+	for s.Scan() {
+		line++
+		text := s.Text()
+		fields := strings.Fields(strings.TrimPrefix(text, "\uFEFF"))
+		if len(fields) == 0 {
+			continue
+		}
+		e, err := parseHistoryEntry(fields)
+		if err != nil {
+			errs = append(errs, &LineError{line, text, err.Error()})
+			continue
+		}
+
+		var problems []string
+		if first, ok := words[e.Word]; ok {
+			problems = append(problems, fmt.Sprintf("duplicate of line %d", first))
+		} else {
+			words[e.Word] = line
+		}
+		if !e.Date.IsZero() {
+			if first, ok := dates[e.Date]; ok {
+				problems = append(problems, fmt.Sprintf("date already on line %d", first))
+			} else {
+				dates[e.Date] = line
+			}
+		}
+		if e.Day != 0 {
+			if first, ok := numbers[e.Day]; ok {
+				problems = append(problems, fmt.Sprintf("day number already on line %d", first))
+			} else {
+				numbers[e.Day] = line
+			}
+		}
+		if e.Day != 0 && !e.Date.IsZero() {
+			if epoch.IsZero() {
+				epoch, epochLine = e.Date.AddDate(0, 0, -e.Day), line
+			} else if want := days(epoch, e.Date); want != e.Day {
+				problems = append(problems, fmt.Sprintf("day number %d does not match line %d, want %d", e.Day, epochLine, want))
+			}
+		}
+		if len(problems) > 0 {
+			errs = append(errs, &LineError{line, text, strings.Join(problems, ", ")})
+			continue
+		}
+		entries = append(entries, e)
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if len(errs) > 0 {
+		return nil, &LoadError{errs}
+	}
+	return NewHistory(entries), nil
+}
+
+// parseHistoryEntry přečte řádek historie rozdělený na pole.
+func parseHistoryEntry(fields []string) (HistoryEntry, error) {
+	e := HistoryEntry{Word: Canonical(fields[len(fields)-1])}
+	prefix := fields[:len(fields)-1]
+	if len(prefix) > 2 {
+		return e, fmt.Errorf("want [day number] [date] word")
+	}
+	if len(prefix) > 0 && !strings.Contains(prefix[0], "-") {
+		n, err := strconv.Atoi(prefix[0])
+		if err != nil || n <= 0 {
+			return e, fmt.Errorf("invalid day number %q", prefix[0])
+		}
+		e.Day = n
+		prefix = prefix[1:]
+	}
+	if len(prefix) > 0 {
+		date, err := time.Parse(dateLayout, prefix[0])
+		if err != nil {
+			return e, fmt.Errorf("invalid date %q", prefix[0])
+		}
+		e.Date = date
+		prefix = prefix[1:]
+	}
+	if len(prefix) > 0 {
+		return e, fmt.Errorf("want [day number] [date] word")
+	}
+	return e, nil
+}
+
+// History vrátí historii slovníku (z loaderu, doplněnou o MarkUsed); nil =
+// slovník historii nemá.
+func (d *Dictionary) History() *History {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.history
+}
+
+// SetHistory nahradí historii slovníku (např. History.Before pro rozbor
+// staré hry) a podle ní nastaví Historic všem slovům.
+func (d *Dictionary) SetHistory(h *History) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.history = h
+	for _, w := range d.order {
+		node := d.node(w)
+		if historic := h.Contains(w); historic != node.Word.Historic {
+			c := *node.Word
+			c.Historic = historic
+			d.replace(node, &c)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package dict
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func historyWords(h *History) []string {
+	var words []string
+	for _, e := range h.entries() {
+		words = append(words, e.Word)
+	}
+	return words
+}
+
+// testHistory: položky bez data (vrána, pivko, mleko) jsou mezi datovanými
+// i za poslední z nich.
+const testHistory = `1 2024-01-01 krava
+vrána
+2024-01-03 barva
+pivko
+4 kráva
+mleko
+`
+
+func TestHistoryBefore(t *testing.T) {
+	h, err := LoadHistoryFromReader(strings.NewReader(testHistory))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		date string
+		want []string
+	}{
+		{"2023-12-31", nil},
+		{"2024-01-01", nil},
+		{"2024-01-02", []string{"krava"}},
+		{"2024-01-03", []string{"krava", "vrána"}},
+		// pivko je před kráva (4. 1.), mleko za poslední položkou s datem
+		{"2024-01-04", []string{"krava", "vrána", "barva", "pivko"}},
+		{"2024-01-05", []string{"krava", "vrána", "barva", "pivko", "kráva"}},
+		{"2025-01-01", []string{"krava", "vrána", "barva", "pivko", "kráva"}},
+	}
+	for _, tt := range tests {
+		if got := historyWords(h.Before(date(tt.date))); !slices.Equal(got, tt.want) {
+			t.Errorf("Before(%s) = %q, want %q", tt.date, got, tt.want)
+		}
+		// číslo hádanky se dopočítá z data (epocha z prvního řádku)
+		n := days(date("2023-12-31"), date(tt.date))
+		if got := historyWords(h.BeforeDay(n)); !slices.Equal(got, tt.want) {
+			t.Errorf("BeforeDay(%d) = %q, want %q", n, got, tt.want)
+		}
+	}
+
+	var nilHistory *History
+	if got := nilHistory.Before(date("2024-01-01")); got.Len() != 0 {
+		t.Errorf("nil Before = %q", historyWords(got))
+	}
+}
+
+func TestHistoryUndated(t *testing.T) {
+	h := NewHistory([]HistoryEntry{{Word: "krava"}, {Word: "barva"}})
+	if got := h.Before(date("2024-01-01")); got.Len() != 0 {
+		t.Errorf("Before = %q, want none", historyWords(got))
+	}
+	if got := h.BeforeDay(100); got.Len() != 0 {
+		t.Errorf("BeforeDay = %q, want none", historyWords(got))
+	}
+}
+
+func TestHistoryDays(t *testing.T) {
+	h, err := LoadHistoryFromReader(strings.NewReader(testHistory))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := h.OnDay(3); !ok || e.Word != "barva" || !e.Date.Equal(date("2024-01-03")) {
+		t.Errorf("OnDay(3) = %v, %v", e, ok)
+	}
+	if e, ok := h.On(date("2024-01-04")); !ok || e.Word != "kráva" || e.Day != 4 {
+		t.Errorf("On(2024-01-04) = %v, %v", e, ok)
+	}
+	if _, ok := h.OnDay(2); ok {
+		t.Errorf("OnDay(2): undated entry found")
+	}
+}
+
+func TestLoadHistoryErrors(t *testing.T) {
+	tests := []struct {
+		in   string
+		errs []int
+	}{
+		{"krava\nkrava\n", []int{2}},
+		{"2024-01-01 krava\n2024-01-01 barva\n", []int{2}},
+		{"1 krava\n1 barva\n", []int{2}},
+		{"1 2024-01-01 krava\n3 2024-01-02 barva\n", []int{2}},
+		{"x krava\n2024-13-01 barva\n0 vrána\n1 2 3 mleko\n", []int{1, 2, 3, 4}},
+	}
+	for _, tt := range tests {
+		_, err := LoadHistoryFromReader(strings.NewReader(tt.in))
+		var lerr *LoadError
+		if !errors.As(err, &lerr) {
+			t.Errorf("LoadHistory(%q): %v, want *LoadError", tt.in, err)
+			continue
+		}
+		if got := errorLines(lerr.Errors); !slices.Equal(got, tt.errs) {
+			t.Errorf("LoadHistory(%q): lines %v, want %v", tt.in, got, tt.errs)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package dict
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Loader = nastavení načítání slovníku; nulová hodnota čte českou abecedu
+// a každý řádek, který není přesně slovo slovníku (případně s váhou, viz
+// DictionaryWord.Weight), je chyba.
+type Loader struct {
+	Alphabet *Alphabet // nil = Czech
+
+	// Lenient = řádky s BOM, CRLF, bílými znaky okolo, velkými písmeny,
+	// rozloženými znaky (NFD) nebo duplicitní slova (a prázdné řádky) nejsou
+	// chyba: loader je opraví (vynechá) a zapíše do Dictionary.Fixed.
+	Lenient bool
+}
+
+// LineError = problém na řádku Line souboru slovníku (nebo historie); Text je řádek tak,
+// jak byl v souboru.
+type LineError struct {
+	Line int
+	Text string
+	Msg  string
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %q: %s", e.Line, e.Text, e.Msg)
+}
+
+// LoadError = všechny chybné řádky slovníku (nebo historie).
+type LoadError struct {
+	Errors []*LineError
+}
+
+// maxLoadErrors = kolik chyb vypíše LoadError.Error (všechny jsou v Errors).
+const maxLoadErrors = 10
+
+func (e *LoadError) Error() string {
+	lines := make([]string, 0, maxLoadErrors+1)
+	for i, le := range e.Errors {
+		if i == maxLoadErrors {
+			lines = append(lines, fmt.Sprintf("and %d more", len(e.Errors)-i))
+			break
+		}
+		lines = append(lines, le.Error())
+	}
+	return fmt.Sprintf("%d invalid lines:\n%s", len(e.Errors), strings.Join(lines, "\n"))
+}
+
+func (e *LoadError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, le := range e.Errors {
+		errs[i] = le
+	}
+	return errs
+}
+
+func LoadDictionary(filePath string, history *History) (*Dictionary, error) {
+	return Loader{}.Load(filePath, history)
+}
+
+// LoadDictionaryFromReader je verze LoadDictionary čtoucí z io.Reader (např. pro WASM,
+// kde není souborový systém — data se předají jako bytes). Délku slov určí
+// první platný řádek; chybné řádky vrátí jako *LoadError.
+func LoadDictionaryFromReader(r io.Reader, history *History) (*Dictionary, error) {
+	return Loader{}.LoadFromReader(r, history)
+}
+
+// Load je LoadDictionary s nastavením l.
+func (l Loader) Load(filePath string, history *History) (*Dictionary, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return l.LoadFromReader(f, history)
+}
+
+// LoadFromReader je LoadDictionaryFromReader s nastavením l.
+func (l Loader) LoadFromReader(r io.Reader, history *History) (*Dictionary, error) {
+	words := NewDictionary(l.Alphabet)
+	words.history = history
+
+	s := bufio.NewScanner(r)
+	s.Split(scanLines)
+	indexes := make([]int, 0, 8)
+	seen := make(map[string]int) // slovo → řádek
+	var errs []*LineError
+	line := 0
+
+	for s.Scan() {
+		line++
+		text := s.Text()
+		if !utf8.ValidString(text) {
+			errs = append(errs, &LineError{line, text, "invalid UTF-8"})
+			continue
+		}
+
+		w, changes := normalize(text, line == 1)
+		var problems []string
+		if len(changes) > 0 {
+			if !l.Lenient {
+				problems = changes
+			} else {
+				words.Fixed = append(words.Fixed, &LineError{line, text, strings.Join(changes, ", ")})
+			}
+		}
+		if w == "" {
+			if !l.Lenient {
+				errs = append(errs, &LineError{line, text, "empty line"})
+			} else {
+				words.Fixed = append(words.Fixed, &LineError{line, text, "empty line, skipped"})
+			}
+			continue
+		}
+
+		w, weight, err := splitWeight(w)
+		if err != nil {
+			problems = append(problems, err.Error())
+		}
+
+		indexes = indexes[:0]
+		unknown := ""
+		for _, písmeno := range w {
+			index, ok := words.Alphabet.Index(písmeno)
+			if !ok && !strings.ContainsRune(unknown, písmeno) {
+				unknown += string(písmeno)
+				problems = append(problems, fmt.Sprintf("unknown letter %q", písmeno))
+			}
+			indexes = append(indexes, index)
+		}
+
+		if words.Size == 0 && unknown == "" {
+			words.Size = len(indexes)
+		} else if words.Size != 0 && len(indexes) != words.Size {
+			problems = append(problems, fmt.Sprintf("%d letters, want %d", len(indexes), words.Size))
+		}
+
+		if first, ok := seen[w]; ok {
+			if !l.Lenient {
+				problems = append(problems, fmt.Sprintf("duplicate of line %d", first))
+			} else if len(problems) == 0 {
+				words.Fixed = append(words.Fixed, &LineError{line, text, fmt.Sprintf("duplicate of line %d, skipped", first)})
+				continue
+			}
+		} else {
+			seen[w] = line
+		}
+
+		if len(problems) > 0 {
+			errs = append(errs, &LineError{line, text, strings.Join(problems, ", ")})
+			continue
+		}
+		words.insert(indexes, w, true, history.Contains(w), weight)
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if len(errs) > 0 {
+		return nil, &LoadError{errs}
+	}
+	return words, nil
+}
+
+// splitWeight rozdělí řádek na slovo a nepovinnou váhu (oddělenou mezerou
+// nebo tabulátorem); bez váhy vrátí 1.
+func splitWeight(line string) (string, float64, error) {
+	fields := strings.Fields(line)
+	switch len(fields) {
+	case 1:
+		return fields[0], 1, nil
+	case 2:
+		weight, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil || weight < 0 || math.IsInf(weight, 0) || math.IsNaN(weight) {
+			return fields[0], 1, fmt.Errorf("invalid weight %q", fields[1])
+		}
+		return fields[0], weight, nil
+	}
+	return fields[0], 1, fmt.Errorf("want a word and an optional weight")
+}
+
+// normalize odstraní z řádku BOM (jen na prvním řádku), CR, bílé znaky okolo
+// a slovo převede na Canonical; vrátí i seznam provedených změn.
+func normalize(text string, first bool) (string, []string) {
+	var changes []string
+	w := text
+	if first && strings.HasPrefix(w, "\uFEFF") {
+		w = strings.TrimPrefix(w, "\uFEFF")
+		changes = append(changes, "byte order mark")
+	}
+	if strings.HasSuffix(w, "\r") {
+		w = strings.TrimSuffix(w, "\r")
+		changes = append(changes, "CRLF line ending")
+	}
+	if t := strings.TrimSpace(w); t != w {
+		w = t
+		changes = append(changes, "leading or trailing whitespace")
+	}
+	end := strings.IndexFunc(w, unicode.IsSpace) // za slovem může být váha
+	if end < 0 {
+		end = len(w)
+	}
+	if strings.IndexFunc(w[:end], unicode.IsUpper) >= 0 {
+		changes = append(changes, "uppercase letters")
+	}
+	if word := Canonical(w[:end]); word != strings.ToLower(w[:end]) {
+		changes = append(changes, "decomposed letters (NFD)")
+		w = word + w[end:]
+	} else if word != w[:end] {
+		w = word + w[end:]
+	}
+	return w, changes
+}
+
+// scanLines je bufio.ScanLines, které na konci řádku nechá '\r' (CRLF
+// rozpozná až normalize).
+func scanLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
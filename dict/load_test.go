@@ -0,0 +1,80 @@
+package dict
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLoader(t *testing.T) {
+	tests := []struct {
+		in      string
+		lenient bool
+		errs    []int    // čísla chybných řádků; nil = načte se
+		fixed   []int    // čísla opravených řádků (Lenient)
+		words   []string // slova slovníku v pořadí načtení
+	}{
+		{in: "krava\nbarva\n", words: []string{"krava", "barva"}},
+		{in: "krava\nbarva", words: []string{"krava", "barva"}},
+		{in: "\uFEFFkrava\r\nbarva\r\n", errs: []int{1, 2}},
+		{in: "\uFEFFkrava\r\nbarva\r\n", lenient: true, fixed: []int{1, 2}, words: []string{"krava", "barva"}},
+		{in: "krava\n BARVA\n", errs: []int{2}},
+		{in: "krava\n BARVA\n", lenient: true, fixed: []int{2}, words: []string{"krava", "barva"}},
+		{in: "kra\u0301va\n", errs: []int{1}},
+		{in: "kra\u0301va\n", lenient: true, fixed: []int{1}, words: []string{"kráva"}},
+		{in: "krava\n\nbarva\n", errs: []int{2}},
+		{in: "krava\n\nbarva\n", lenient: true, fixed: []int{2}, words: []string{"krava", "barva"}},
+		{in: "krava\nbarva\nkrava\n", errs: []int{3}},
+		{in: "krava\nbarva\nkrava\n", lenient: true, fixed: []int{3}, words: []string{"krava", "barva"}},
+		// neplatná slova a váhy jsou chyba i v Lenient
+		{in: "krava\nkr1va\nkrav\nbarva x\nmleko 1 2\npivko -1\n", errs: []int{2, 3, 4, 5, 6}},
+		{in: "krava\nkr1va\nkrav\nbarva x\nmleko 1 2\npivko -1\n", lenient: true, errs: []int{2, 3, 4, 5, 6}},
+		{in: "krava\nkrava x\n", lenient: true, errs: []int{2}},
+		{in: "krava\n\xff\xfe\n", lenient: true, errs: []int{2}},
+	}
+	for _, tt := range tests {
+		d, err := Loader{Lenient: tt.lenient}.LoadFromReader(strings.NewReader(tt.in), nil)
+		if tt.errs != nil {
+			var lerr *LoadError
+			if !errors.As(err, &lerr) {
+				t.Errorf("Load(%q, lenient %v): %v, want *LoadError", tt.in, tt.lenient, err)
+				continue
+			}
+			if got := errorLines(lerr.Errors); !slices.Equal(got, tt.errs) {
+				t.Errorf("Load(%q, lenient %v): lines %v, want %v", tt.in, tt.lenient, got, tt.errs)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Load(%q, lenient %v): %v", tt.in, tt.lenient, err)
+			continue
+		}
+		if got := errorLines(d.Fixed); !slices.Equal(got, tt.fixed) {
+			t.Errorf("Load(%q, lenient %v): fixed %v, want %v", tt.in, tt.lenient, got, tt.fixed)
+		}
+		if !slices.Equal(d.order, tt.words) {
+			t.Errorf("Load(%q, lenient %v): words %q, want %q", tt.in, tt.lenient, d.order, tt.words)
+		}
+	}
+}
+
+func TestLoaderWeight(t *testing.T) {
+	d, err := LoadDictionaryFromReader(strings.NewReader("kráva 2.5\nbarva\t0\nmleko\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for word, want := range map[string]float64{"kráva": 2.5, "barva": 0, "mleko": 1} {
+		if dw, ok := d.Lookup(word); !ok || dw.Weight != want {
+			t.Errorf("Lookup(%q) = %+v, %v, want weight %v", word, dw, ok, want)
+		}
+	}
+}
+
+func errorLines(errs []*LineError) []int {
+	var lines []int
+	for _, e := range errs {
+		lines = append(lines, e.Line)
+	}
+	return lines
+}
@@ -0,0 +1,125 @@
+package dict
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MatchOptions = volby pro Dictionary.Match.
+type MatchOptions struct {
+	Fold     bool   // písmena se porovnávají bez diakritiky (podle Conv)
+	Contains string // písmena, která slovo musí obsahovat ("aa" = aspoň dvě a)
+	Excludes string // písmena, která slovo obsahovat nesmí
+}
+
+// Match najde slova odpovídající vzoru (v pořadí trie): na každou pozici
+// jedno písmeno, ? = libovolné písmeno, [abc] = jedno z písmen, [^abc] =
+// žádné z nich. Větve, které vzoru ani Excludes nevyhoví, se neprocházejí.
+func (d *Dictionary) Match(pattern string, opts MatchOptions) ([]*DictionaryWord, error) {
+	a := d.Alphabet
+	// key = index, podle kterého se písmena porovnávají
+	key := func(index int) int {
+		if opts.Fold {
+			return a.ConvIndex[index]
+		}
+		return index
+	}
+	keyOf := func(písmeno rune) (int, error) {
+		index, ok := a.Index(písmeno)
+		if !ok {
+			return 0, fmt.Errorf("dict: pattern %q: unknown letter %q", pattern, písmeno)
+		}
+		return key(index), nil
+	}
+	set := func(letters string) ([]bool, error) {
+		keys := make([]bool, a.Size())
+		for _, písmeno := range letters {
+			k, err := keyOf(písmeno)
+			if err != nil {
+				return nil, err
+			}
+			keys[k] = true
+		}
+		return keys, nil
+	}
+
+	excluded, err := set(opts.Excludes)
+	if err != nil {
+		return nil, err
+	}
+	need := make([]int, a.Size())
+	for _, písmeno := range opts.Contains {
+		k, err := keyOf(písmeno)
+		if err != nil {
+			return nil, err
+		}
+		need[k]++
+	}
+
+	var allowed [][]bool // pozice → index písmene → smí tam být
+	rest := []rune(pattern)
+	for len(rest) > 0 {
+		var keys []bool
+		switch rest[0] {
+		case '?':
+			keys = make([]bool, a.Size())
+			for k := range keys {
+				keys[k] = true
+			}
+			rest = rest[1:]
+		case '[':
+			end := slices.Index(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("dict: pattern %q: missing ]", pattern)
+			}
+			class, negate := strings.CutPrefix(string(rest[1:end]), "^")
+			if keys, err = set(class); err != nil {
+				return nil, err
+			}
+			if negate {
+				for k := range keys {
+					keys[k] = !keys[k]
+				}
+			}
+			rest = rest[end+1:]
+		default:
+			if keys, err = set(string(rest[0])); err != nil {
+				return nil, err
+			}
+			rest = rest[1:]
+		}
+
+		pos := make([]bool, a.Size())
+		for index := range pos {
+			k := key(index)
+			pos[index] = keys[k] && !excluded[k]
+		}
+		allowed = append(allowed, pos)
+	}
+	if len(allowed) != d.Size {
+		return nil, fmt.Errorf("dict: pattern %q has %d letters, want %d", pattern, len(allowed), d.Size)
+	}
+
+	var words []*DictionaryWord
+	count := make([]int, a.Size())
+	d.Walk(
+		func(pos, index int) bool {
+			return allowed[pos][index]
+		},
+		func(indexes []int, dw *DictionaryWord) bool {
+			clear(count)
+			for _, index := range indexes {
+				count[key(index)]++
+			}
+			for k, n := range need {
+				if count[k] < n {
+					return true
+				}
+			}
+			words = append(words, dw)
+			return true
+		},
+	)
+	return words, nil
+}
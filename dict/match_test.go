@@ -0,0 +1,57 @@
+package dict
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	d, err := LoadDictionaryFromReader(strings.NewReader("krava\nkráva\nbarva\nvrána\nmleko\npivko\nbabka\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		pattern string
+		opts    MatchOptions
+		want    []string // seřazená; nil = chyba
+	}{
+		{"?????", MatchOptions{}, []string{"babka", "barva", "krava", "kráva", "mleko", "pivko", "vrána"}},
+		{"kr?va", MatchOptions{}, []string{"krava", "kráva"}},
+		{"krava", MatchOptions{}, []string{"krava"}},
+		{"krava", MatchOptions{Fold: true}, []string{"krava", "kráva"}},
+		{"??[áe]??", MatchOptions{}, []string{"kráva", "mleko", "vrána"}},
+		{"??[^áe]??", MatchOptions{}, []string{"babka", "barva", "krava", "pivko"}},
+		{"??[^a]??", MatchOptions{Fold: true}, []string{"babka", "barva", "mleko", "pivko"}},
+		{"????a", MatchOptions{Excludes: "r"}, []string{"babka"}},
+		{"?????", MatchOptions{Contains: "aa"}, []string{"babka", "barva", "krava"}},
+		{"?????", MatchOptions{Contains: "aa", Fold: true}, []string{"babka", "barva", "krava", "kráva", "vrána"}},
+		{"?????", MatchOptions{Contains: "bb"}, []string{"babka"}},
+		{"?????", MatchOptions{Contains: "kv", Excludes: "áo"}, []string{"krava"}},
+		{"????", MatchOptions{}, nil},
+		{"??[ab??", MatchOptions{}, nil},
+		{"??1??", MatchOptions{}, nil},
+		{"?????", MatchOptions{Contains: "x1"}, nil},
+	}
+	for _, tt := range tests {
+		got, err := d.Match(tt.pattern, tt.opts)
+		if tt.want == nil {
+			if err == nil {
+				t.Errorf("Match(%q, %+v): want error", tt.pattern, tt.opts)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Match(%q, %+v): %v", tt.pattern, tt.opts, err)
+			continue
+		}
+		var words []string
+		for _, dw := range got {
+			words = append(words, dw.Word)
+		}
+		slices.Sort(words)
+		if !slices.Equal(words, tt.want) {
+			t.Errorf("Match(%q, %+v) = %q, want %q", tt.pattern, tt.opts, words, tt.want)
+		}
+	}
+}
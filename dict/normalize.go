@@ -0,0 +1,41 @@
+package dict
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Canonical převede text do tvaru, ve kterém jsou slova slovníku: malá
+// písmena a složené znaky (NFC). Žádný znak nezahodí; co složit nejde,
+// nechá, jak je.
+func Canonical(w string) string {
+	if !strings.ContainsFunc(w, unicode.IsUpper) && norm.NFC.IsNormalString(w) {
+		return w
+	}
+	return norm.NFC.String(strings.ToLower(w))
+}
+
+// LetterError = slovo obsahuje znak, který v abecedě není (ani po Canonical).
+type LetterError struct {
+	Word   string
+	Letter rune
+}
+
+func (e *LetterError) Error() string {
+	return fmt.Sprintf("dict: %q: unknown letter %q", e.Word, e.Letter)
+}
+
+// Normalize převede slovo do tvaru abecedy (viz Canonical) a ověří, že jsou
+// v něm jen písmena abecedy; jinak vrátí *LetterError.
+func (a *Alphabet) Normalize(w string) (string, error) {
+	w = Canonical(w)
+	for _, písmeno := range w {
+		if _, ok := a.Indexes[písmeno]; !ok {
+			return w, &LetterError{w, písmeno}
+		}
+	}
+	return w, nil
+}
@@ -0,0 +1,89 @@
+package dict
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCanonical(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"kráva", "kráva"},
+		{"KRÁVA", "kráva"},
+		{"kra\u0301va", "kráva"},
+		{"KRA\u0301VA", "kráva"},
+		{"u\u030al", "ůl"},
+		{"ka\u0308se", "käse"},
+		// co složit nejde, zůstane
+		{"x\u0301", "x\u0301"},
+		{"ab1", "ab1"},
+	}
+	for _, tt := range tests {
+		if got := Canonical(tt.in); got != tt.want {
+			t.Errorf("Canonical(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeAndStrip(t *testing.T) {
+	tests := []struct {
+		in         string
+		normalized string
+		stripped   string
+		letter     rune // písmeno mimo abecedu; 0 = žádné
+	}{
+		{"kráva", "kráva", "krava", 0},
+		{"KRA\u0301VA", "kráva", "krava", 0},
+		{"Žlutý", "žlutý", "zluty", 0},
+		{"käse", "käse", "käse", 'ä'},
+		{"kr1va", "kr1va", "kr1va", '1'},
+		{"x\u0301a", "x\u0301a", "x\u0301a", '\u0301'},
+	}
+	for _, tt := range tests {
+		for _, f := range []struct {
+			name string
+			fn   func(string) (string, error)
+			want string
+		}{
+			{"Normalize", Czech.Normalize, tt.normalized},
+			{"Strip", Czech.Strip, tt.stripped},
+			{"StripDiacritic", StripDiacritic, tt.stripped},
+		} {
+			got, err := f.fn(tt.in)
+			var lerr *LetterError
+			switch {
+			case got != f.want:
+				t.Errorf("%s(%q) = %q, want %q", f.name, tt.in, got, f.want)
+			case tt.letter == 0 && err != nil:
+				t.Errorf("%s(%q): %v", f.name, tt.in, err)
+			case tt.letter != 0 && (!errors.As(err, &lerr) || lerr.Letter != tt.letter):
+				t.Errorf("%s(%q): %v, want unknown letter %q", f.name, tt.in, err, tt.letter)
+			}
+		}
+	}
+}
+
+// TestCanonicalLookup: dotazy i úpravy slovníku převádějí slovo na Canonical.
+func TestCanonicalLookup(t *testing.T) {
+	d := queryDictionary(t)
+	for _, word := range []string{"KRÁVA", "kra\u0301va"} {
+		if dw, ok := d.Lookup(word); !ok || dw.Word != "kráva" || !d.Contains(word) {
+			t.Errorf("Lookup(%q) = %v, %v", word, dw, ok)
+		}
+		if n := len(d.LookupBase(word)); n != 2 {
+			t.Errorf("LookupBase(%q): %d words, want 2", word, n)
+		}
+	}
+	if d.LookupBase("kräva") != nil {
+		t.Errorf("LookupBase(%q): want no words", "kräva")
+	}
+	if _, err := d.MarkUsed("KRA\u0301VA", time.Time{}); err != nil {
+		t.Errorf("MarkUsed: %v", err)
+	}
+	if !d.History().Contains("kráva") {
+		t.Errorf("MarkUsed(%q): %q not in the history", "KRA\u0301VA", "kráva")
+	}
+	if err := d.Remove("BARVA"); err != nil || d.Contains("barva") {
+		t.Errorf("Remove(%q): %v", "BARVA", err)
+	}
+}
@@ -0,0 +1,66 @@
+package dict
+
+import (
+	"iter"
+	"slices"
+)
+
+// Len = počet slov slovníku.
+func (d *Dictionary) Len() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.len
+}
+
+// AnswerCount = počet slov, která ještě mohou být řešením (Possible).
+func (d *Dictionary) AnswerCount() int {
+	n := 0
+	d.Walk(func(int, int) bool { return true }, func(_ []int, dw *DictionaryWord) bool {
+		if dw.Possible() {
+			n++
+		}
+		return true
+	})
+	return n
+}
+
+// Lookup najde slovo přesně (včetně diakritiky, po Canonical).
+func (d *Dictionary) Lookup(word string) (*DictionaryWord, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	node := d.node(Canonical(word))
+	if node == nil || node.Word == nil {
+		return nil, false
+	}
+	return node.Word, true
+}
+
+// LookupBase vrátí slova, která se od word liší nanejvýš diakritikou
+// (v pořadí, ve kterém byla načtena); slovo s písmenem mimo abecedu žádná nemá.
+func (d *Dictionary) LookupBase(word string) []*DictionaryWord {
+	base, err := d.Alphabet.Strip(word)
+	if err != nil {
+		return nil
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return slices.Clone(d.bases[base])
+}
+
+// Contains = slovo je ve slovníku (přesně, včetně diakritiky, po Canonical).
+func (d *Dictionary) Contains(word string) bool {
+	_, ok := d.Lookup(word)
+	return ok
+}
+
+// All vrátí slova slovníku v pořadí abecedy (podle indexů písmen). Během
+// procházení drží zámek pro čtení (viz Walk): tělo cyklu slovník nesmí volat,
+// natož měnit (Add, Remove, MarkUsed by se zablokovaly); kdo to potřebuje,
+// ať si slova nejdřív posbírá (slices.Collect).
+func (d *Dictionary) All() iter.Seq[*DictionaryWord] {
+	return func(yield func(*DictionaryWord) bool) {
+		d.Walk(func(int, int) bool { return true }, func(_ []int, dw *DictionaryWord) bool {
+			return yield(dw)
+		})
+	}
+}
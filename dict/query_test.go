@@ -0,0 +1,51 @@
+package dict
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func queryDictionary(t *testing.T) *Dictionary {
+	t.Helper()
+	d, err := LoadDictionaryFromReader(strings.NewReader("krava\nkráva\nbarva\nmleko\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestLookup(t *testing.T) {
+	d := queryDictionary(t)
+	if d.Len() != 4 || d.AnswerCount() != 4 {
+		t.Errorf("Len, AnswerCount = %d, %d, want 4, 4", d.Len(), d.AnswerCount())
+	}
+	tests := []struct {
+		word  string
+		found bool
+		base  []string // LookupBase v pořadí načtení
+	}{
+		{"krava", true, []string{"krava", "kráva"}},
+		{"kráva", true, []string{"krava", "kráva"}},
+		{"krává", false, []string{"krava", "kráva"}},
+		{"bárva", false, []string{"barva"}},
+		{"pivko", false, nil},
+		{"krav", false, nil},
+	}
+	for _, tt := range tests {
+		dw, ok := d.Lookup(tt.word)
+		if ok != tt.found || ok && dw.Word != tt.word {
+			t.Errorf("Lookup(%q) = %v, %v, want %v", tt.word, dw, ok, tt.found)
+		}
+		if d.Contains(tt.word) != tt.found {
+			t.Errorf("Contains(%q) = %v, want %v", tt.word, !tt.found, tt.found)
+		}
+		var base []string
+		for _, dw := range d.LookupBase(tt.word) {
+			base = append(base, dw.Word)
+		}
+		if !slices.Equal(base, tt.base) {
+			t.Errorf("LookupBase(%q) = %q, want %q", tt.word, base, tt.base)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+//go:build embeddata
+
+package embedded
+
+import _ "embed"
+
+//go:embed db-hacky.bin
+var dictData []byte
+
+//go:embed luck.gob
+var luckData []byte
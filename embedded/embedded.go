@@ -0,0 +1,40 @@
+// Package embedded přibalí výchozí data (zkompilovaný slovník a luck.gob)
+// přímo do binárky nebo .wasm, takže nejsou potřeba žádné soubory.
+//
+// Data se přibalí jen s build tagem embeddata; předtím je vygenerujte:
+//
+//	go generate ./embedded
+//	go build -tags embeddata …
+//
+// Bez tagu vrací Dictionary a Engine ErrNotEmbedded.
+package embedded
+
+//go:generate go run ../compile -dict ../db-hacky.txt -history ../used.txt -o db-hacky.bin
+//go:generate cp ../luck.gob luck.gob
+
+import (
+	"errors"
+
+	"github.com/pracj3am/wordle-solver/analyzer"
+	"github.com/pracj3am/wordle-solver/dict"
+)
+
+// ErrNotEmbedded = binárka je přeložená bez build tagu embeddata.
+var ErrNotEmbedded = errors.New("embedded: built without the embeddata tag")
+
+// Dictionary vrátí přibalený slovník (historie z used.txt při generování).
+func Dictionary() (*dict.Dictionary, error) {
+	if len(dictData) == 0 {
+		return nil, ErrNotEmbedded
+	}
+	return dict.LoadCompiled(dictData)
+}
+
+// Engine vrátí analyzer.Engine nad přibaleným slovníkem a luck.gob; answers
+// jako u analyzer.NewEngineFromBytes.
+func Engine(answers []string) (*analyzer.Engine, error) {
+	if len(dictData) == 0 {
+		return nil, ErrNotEmbedded
+	}
+	return analyzer.NewEngineFromCompiled(dictData, luckData, answers)
+}
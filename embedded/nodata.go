@@ -0,0 +1,5 @@
+//go:build !embeddata
+
+package embedded
+
+var dictData, luckData []byte
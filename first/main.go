@@ -13,9 +13,11 @@ import (
 	pr "github.com/pracj3am/wordle-solver/progress"
 )
 
+// LuckStat viz analyzer.LuckStat (Weights = histogram vážený DictionaryWord.Weight).
 type LuckStat struct {
 	Histogram map[int]int
 	Sum       float64
+	Weights   map[int]float64
 }
 
 type Skills struct {
@@ -23,65 +25,57 @@ type Skills struct {
 	Human *odds.Skill // human nezná použitý slova
 }
 
+// CalculateOdds viz main.CalculateOdds; tip word je slovo z db.txt (bez
+// diakritiky, nemusí být mezi all).
 func CalculateOdds(
 	word string,
-	all []dict.DictionaryWord,
+	all []*dict.DictionaryWord,
 	ppr *pr.Progress,
 ) (
 	float64, float64, *LuckStat,
 ) {
 	var sumNotUsed, sum float64
 	var luck LuckStat
-	var countNotUsed, count int
+	var weightNotUsed, weight float64
 
 	luck.Histogram = make(map[int]int)
-
-	for _, dw := range all {
-		var counter, counterNotUsed int
-
-		if dw.WithoutDiacritics != word {
-			pr := ppr.Clone()
-			pr.ResetRound()
-
-			pr.Guess(word, dw.Word)
-			counter, counterNotUsed, _ = pr.WordsLeft(false)
-
-			if counterNotUsed == 0 && !dw.Used {
-				panic(fmt.Sprintf("%s + %s: counter 0", word, dw.Word))
+	luck.Weights = make(map[int]float64)
+	base, _ := dict.StripDiacritic(word) // písmena mimo abecedu zůstanou
+	guess := &dict.DictionaryWord{Word: word, WithoutDiacritics: base}
+
+	for pat, bucket := range ppr.Partition(word, all) {
+		for _, group := range pr.SplitRevealed(pat, bucket) {
+			groupCounter, groupNotUsed := ppr.CountWords(group)
+
+			for _, dw := range group {
+				counter, counterNotUsed := groupCounter, groupNotUsed
+
+				if ppr.Rules().SameWord(dw, guess) {
+					counter, counterNotUsed = 0, 0
+				}
+
+				sum += dw.Weight * float64(counter)
+				weight += dw.Weight
+
+				if dw.Possible() {
+					luck.Histogram[counterNotUsed]++
+					luck.Weights[counterNotUsed] += dw.Weight
+					luck.Sum += dw.Weight
+					sumNotUsed += dw.Weight * float64(counterNotUsed)
+					weightNotUsed += dw.Weight
+				}
 			}
 		}
-
-		sum += float64(counter)
-		count++
-
-		if !dw.Used {
-			luck.Histogram[counterNotUsed]++
-			luck.Sum++
-			sumNotUsed += float64(counterNotUsed)
-			countNotUsed++
-		}
 	}
 
-	return sum / float64(count), sumNotUsed / float64(countNotUsed), &luck
-}
-
-func LoadSolutions(filePath string, history map[string]bool) ([]dict.DictionaryWord, error) {
-	words := make([]dict.DictionaryWord, 0, 2000)
-	f, err := os.Open(filePath)
-	if err != nil {
-		return nil, err
+	var avg, avgNotUsed float64
+	if weight > 0 {
+		avg = sum / weight
 	}
-	defer f.Close()
-	s := bufio.NewScanner(f)
-
-	for s.Scan() {
-		words = append(words, dict.DictionaryWord{
-			Word:              s.Text(),
-			WithoutDiacritics: dict.StripDiacritic(s.Text()),
-			Used:              history[s.Text()],
-		})
+	if weightNotUsed > 0 {
+		avgNotUsed = sumNotUsed / weightNotUsed
 	}
-	return words, s.Err()
+	return avg, avgNotUsed, &luck
 }
 
 func LoadDictionary(filePath string) ([]string, error) {
@@ -112,12 +106,6 @@ func main() {
 		os.Exit(1)
 	}
 
-	solutions, err := LoadSolutions("../db-hacky.txt", history)
-	if err != nil {
-		fmt.Println("loading all words failed", err)
-		os.Exit(1)
-	}
-
 	all, err := LoadDictionary("../db.txt")
 	if err != nil {
 		fmt.Println("loading all words failed", err)
@@ -127,7 +115,8 @@ func main() {
 	luck := make(map[string]*LuckStat)
 	skillHuman := make(map[string]*odds.Skill)
 	skillRobot := make(map[string]*odds.Skill)
-	progress := pr.NewProgress(5, words)
+	progress := pr.NewProgress(words.Size, words, pr.FoldBase())
+	_, _, solutions := progress.WordsLeft(true)
 
 	for i := 1; i <= 1; i++ {
 		wordsLeftRobotWeighted := make([]odds.WeightedWord, len(all))
@@ -135,8 +124,8 @@ func main() {
 
 		for j, w := range all {
 			oddsHuman, oddsRobot, wordLuck := CalculateOdds(w, solutions, progress)
-			wordsLeftRobotWeighted[j] = odds.WeightedWord{w, oddsRobot}
-			wordsLeftHumanWeighted[j] = odds.WeightedWord{w, oddsHuman}
+			wordsLeftRobotWeighted[j] = odds.WeightedWord{Word: w, Weight: oddsRobot}
+			wordsLeftHumanWeighted[j] = odds.WeightedWord{Word: w, Weight: oddsHuman}
 			luck[w] = wordLuck
 
 			fmt.Printf("%d/%d\n", j, len(all))
@@ -149,7 +138,7 @@ func main() {
 		skillRobot = odds.CalculateSkill(wordsLeftRobotWeighted)
 
 		for _, w := range wordsLeftRobotWeighted {
-			if history[w.Word] {
+			if history.Contains(w.Word) {
 				w.Word += " *** "
 			}
 			fmt.Printf("%s %f\n", w.Word, w.Weight)
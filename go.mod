@@ -1,3 +1,5 @@
 module github.com/pracj3am/wordle-solver
 
 go 1.24
+
+require golang.org/x/text v0.28.0
@@ -3,12 +3,21 @@ package main
 import (
 	"bufio"
 	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"os/signal"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"syscall"
+	"time"
 	"unicode/utf8"
 
 	"github.com/pracj3am/wordle-solver/dict"
@@ -16,13 +25,11 @@ import (
 	pr "github.com/pracj3am/wordle-solver/progress"
 )
 
-const (
-	size = 5
-)
-
+// LuckStat viz analyzer.LuckStat (Weights = histogram vážený DictionaryWord.Weight).
 type LuckStat struct {
 	Histogram map[int]int
 	Sum       float64
+	Weights   map[int]float64
 }
 
 type Skills struct {
@@ -75,45 +82,54 @@ func CalculateOdds(
 ) {
 	var sumNotUsed, sum float64
 	var luck LuckStat
-	var countNotUsed, count int
+	var weightNotUsed, weight float64
 
 	luck.Histogram = make(map[int]int)
+	luck.Weights = make(map[int]float64)
 
-	for _, dw := range all {
-		var counter, counterNotUsed int
+	for pat, bucket := range ppr.Partition(word.Word, all) {
+		for _, group := range pr.SplitRevealed(pat, bucket) {
+			groupCounter, groupNotUsed := ppr.CountWords(group)
 
-		if dw.WithoutDiacritics != word.WithoutDiacritics {
-			pr := ppr.Clone()
-			pr.ResetRound()
+			for _, dw := range group {
+				counter, counterNotUsed := groupCounter, groupNotUsed
 
-			pr.Guess(word.Word, dw.Word)
-
-			counter, counterNotUsed, _ = pr.WordsLeft(false)
-			if counterNotUsed == 0 && !dw.Used {
-				panic(fmt.Sprintf("%s + %s: counter 0", word.Word, dw.Word))
-			}
-		}
+				if ppr.Rules().SameWord(dw, word) {
+					counter, counterNotUsed = 0, 0
+				}
 
-		sum += float64(counter)
-		count++
+				sum += dw.Weight * float64(counter)
+				weight += dw.Weight
 
-		if !dw.Used {
-			luck.Histogram[counterNotUsed]++
-			luck.Sum++
-			sumNotUsed += float64(counterNotUsed)
-			countNotUsed++
+				if dw.Possible() {
+					luck.Histogram[counterNotUsed]++
+					luck.Weights[counterNotUsed] += dw.Weight
+					luck.Sum += dw.Weight
+					sumNotUsed += dw.Weight * float64(counterNotUsed)
+					weightNotUsed += dw.Weight
+				}
+			}
 		}
 	}
 
-	return sum / float64(count), sumNotUsed / float64(countNotUsed), &luck
+	var avg, avgNotUsed float64
+	if weight > 0 {
+		avg = sum / weight
+	}
+	if weightNotUsed > 0 {
+		avgNotUsed = sumNotUsed / weightNotUsed
+	}
+	return avg, avgNotUsed, &luck
 }
 
-func makeString(word []rune) string {
-	var buf []byte
-	for _, l := range word {
-		buf = utf8.AppendRune(buf, l)
+// readLine přečte jeden řádek bez koncového \n (resp. \r\n); mezery nechá,
+// ve vzoru znamenají šedou.
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
 	}
-	return string(buf)
+	return strings.TrimRight(line, "\r\n"), nil
 }
 
 func AppendTip(
@@ -131,23 +147,27 @@ func AppendTip(
 	}
 
 	if wordLuck, found := luck[word]; found {
-		var sumBetter int
-		var sumWorse int
+		var sumBetter float64
+		var sumWorse float64
 		var countBetter int
 		for histLeft, histCount := range wordLuck.Histogram {
+			w := float64(histCount)
+			if wordLuck.Weights != nil {
+				w = wordLuck.Weights[histLeft]
+			}
 			if histLeft <= counterNotUsed {
-				sumBetter += histCount
+				sumBetter += w
 				if histLeft > 0 {
 					countBetter++
 				}
 			} else {
-				sumWorse += histCount
+				sumWorse += w
 			}
 		}
 
 		luck := -1.0
 		if sumWorse > 0 || countBetter > 1 {
-			luck = 100 - 100*float64(sumBetter)/(wordLuck.Sum)
+			luck = 100 - 100*sumBetter/(wordLuck.Sum)
 		}
 		tip.Luck = &luck
 	}
@@ -200,26 +220,269 @@ func PrintResuts(tips []Tip) {
 	}
 }
 
+func PrintExplain(progress *pr.Progress, word string) {
+	violations, err := progress.Explain(word)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if len(violations) == 0 {
+		fmt.Printf("%s vyhovuje všem omezením\n", strings.ToUpper(word))
+		return
+	}
+	fmt.Printf("%s není kandidát:\n", strings.ToUpper(word))
+	for _, v := range violations {
+		fmt.Println(" -", v)
+	}
+}
+
+// PrintSummary vypíše přehled omezení a regulární výraz (i s frekvencemi,
+// které výraz nepokryje) pro vložení do jiných nástrojů.
+func PrintSummary(progress *pr.Progress) {
+	fmt.Println(progress.Summary())
+	re, rest := progress.Regexp()
+	fmt.Println(re)
+	for _, c := range rest {
+		fmt.Println(" +", c)
+	}
+}
+
+// MatchCommand = podpříkaz "match": vypíše slova slovníku odpovídající vzoru
+// (viz dict.Dictionary.Match), použitá označí hvězdičkou. Vrátí exit kód.
+func MatchCommand(words *dict.Dictionary, args []string) int {
+	fs := flag.NewFlagSet("match", flag.ExitOnError)
+	fold := fs.Bool("fold", false, "porovnávat bez diakritiky")
+	contains := fs.String("contains", "", "písmena, která slovo musí obsahovat (aa = aspoň dvě)")
+	excludes := fs.String("excludes", "", "písmena, která slovo nesmí obsahovat")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "použití: match [volby] vzor   (? = libovolné písmeno, [abc], [^abc])")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	matches, err := words.Match(dict.Canonical(fs.Arg(0)), dict.MatchOptions{
+		Fold:     *fold,
+		Contains: dict.Canonical(*contains),
+		Excludes: dict.Canonical(*excludes),
+	})
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	for _, dw := range matches {
+		if !dw.Possible() {
+			fmt.Println(dw.Word, "*")
+		} else {
+			fmt.Println(dw.Word)
+		}
+	}
+	fmt.Printf("nalezeno: %d\n", len(matches))
+	return 0
+}
+
+// AnagramCommand = podpříkaz "anagram": pro každé použité slovo z historie
+// vypíše jeho přesmyčky ve slovníku (viz dict.AnagramIndex). Vrátí exit kód.
+func AnagramCommand(words *dict.Dictionary, args []string) int {
+	fs := flag.NewFlagSet("anagram", flag.ExitOnError)
+	fold := fs.Bool("fold", false, "porovnávat bez diakritiky")
+	moved := fs.Bool("moved", false, "jen přesmyčky, kde žádné písmeno nezůstane na místě")
+	answers := fs.Bool("answers", false, "jen možné odpovědi (nepoužitá slova)")
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		fmt.Fprintln(fs.Output(), "použití: anagram [volby]")
+		fs.PrintDefaults()
+		return 2
+	}
+
+	history := words.History()
+	if history.Len() == 0 {
+		fmt.Println("Historie je prázdná, není pro která slova hledat přesmyčky")
+		return 1
+	}
+	index := words.Anagrams(*fold)
+	opts := dict.AnagramOptions{Answers: *answers, Moved: *moved}
+	for _, e := range history.Entries {
+		w := e.Word
+		found := index.Find(w, opts)
+		if len(found) == 0 {
+			continue
+		}
+		list := make([]string, len(found))
+		for i, dw := range found {
+			list[i] = dw.Word
+		}
+		fmt.Printf("%s: %s\n", w, strings.Join(list, " "))
+	}
+	return 0
+}
+
+// state = stav CLI před tahem (pro undo/redo).
+type state struct {
+	turn       int
+	snapshot   pr.Snapshot
+	tips       []Tip
+	luck       map[string]*LuckStat
+	skillRobot map[string]*odds.Skill
+	skillHuman map[string]*odds.Skill
+}
+
+// Session = rozehraná hra; s přepínačem -session se při Ctrl-C uloží a při
+// dalším spuštění pokračuje. Turn = číslo tahu, který se má hrát.
+type Session struct {
+	Turn     int          `json:"turn"`
+	Tips     []Tip        `json:"tips"`
+	Progress *pr.Progress `json:"progress"`
+}
+
+func LoadSession(filePath string, session *Session) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, session)
+}
+
+func SaveSession(filePath string, session *Session) error {
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filePath, data, 0644)
+}
+
+// LiveOdds spočítá metriky pro příští tip nad zbývajícími slovy (luck doplní
+// do mapy) a vypíše je.
+func LiveOdds(
+	wordsLeft []*dict.DictionaryWord,
+	progress *pr.Progress,
+	luck map[string]*LuckStat,
+	history *dict.History,
+) (
+	map[string]*odds.Skill, map[string]*odds.Skill,
+) {
+	wordsLeftRobotWeighted := make([]odds.WeightedWord, len(wordsLeft))
+	wordsLeftHumanWeighted := make([]odds.WeightedWord, len(wordsLeft))
+
+	for j, dw := range wordsLeft {
+		oddsHuman, oddsRobot, wordLuck := CalculateOdds(dw, wordsLeft, progress)
+		wordsLeftRobotWeighted[j] = odds.WeightedWord{Word: dw.Word, Weight: oddsRobot}
+		wordsLeftHumanWeighted[j] = odds.WeightedWord{Word: dw.Word, Weight: oddsHuman}
+		luck[dw.WithoutDiacritics] = wordLuck
+	}
+
+	sort.Sort(odds.ByWeight(wordsLeftHumanWeighted))
+	skillHuman := odds.CalculateSkill(wordsLeftHumanWeighted)
+
+	sort.Sort(odds.ByWeight(wordsLeftRobotWeighted))
+	skillRobot := odds.CalculateSkill(wordsLeftRobotWeighted)
+
+	for _, w := range wordsLeftRobotWeighted {
+		if history.Contains(w.Word) {
+			w.Word += " *** "
+		}
+		fmt.Printf("%s %f\n", w.Word, w.Weight)
+	}
+
+	return skillRobot, skillHuman
+}
+
 func main() {
+	sessionPath := flag.String("session", "", "soubor, do kterého se při Ctrl-C uloží rozehraná hra a ze kterého se pokračuje")
+	rulesFlag := flag.String("rules", "fold", "která písmena hra nerozlišuje: fold (bez diakritiky), strict (každé zvlášť) nebo skupiny, např. uúů,ií")
+	answersPath := flag.String("answers", "", "seznam možných odpovědí (slovo na řádek); prázdné = každé slovo mimo historii")
+	dayFlag := flag.String("day", "", "hrát jako v den 2006-01-02 nebo hádanku číslo N (historie jen před ním)")
+	lenient := flag.Bool("lenient", false, "opravit ve slovníku velká písmena, bílé znaky, CRLF, BOM a duplicity (a vypsat, co se opravilo)")
+	flag.Parse()
+
 	history, err := dict.LoadHistory("used.txt")
 	if err != nil {
 		fmt.Println("loading history failed", err)
 		os.Exit(1)
 	}
+	if *dayFlag != "" {
+		if n, err := strconv.Atoi(*dayFlag); err == nil {
+			history = history.BeforeDay(n)
+		} else if date, err := time.Parse("2006-01-02", *dayFlag); err == nil {
+			history = history.Before(date)
+		} else {
+			fmt.Println("invalid -day", *dayFlag)
+			os.Exit(2)
+		}
+	}
 
-	words, err := dict.LoadDictionary("db-hacky.txt", history)
+	words, err := dict.Loader{Lenient: *lenient}.Load("db-hacky.txt", history)
 	if err != nil {
 		fmt.Println("loading words failed", err)
 		os.Exit(1)
 	}
+	for _, fix := range words.Fixed {
+		fmt.Println("db-hacky.txt:", fix)
+	}
+	if *answersPath != "" {
+		answers, err := dict.LoadAnswers(*answersPath)
+		if err != nil {
+			fmt.Println("loading answers failed", err)
+			os.Exit(1)
+		}
+		words.SetAnswers(answers)
+	}
+
+	switch flag.Arg(0) {
+	case "match":
+		os.Exit(MatchCommand(words, flag.Args()[1:]))
+	case "anagram":
+		os.Exit(AnagramCommand(words, flag.Args()[1:]))
+	}
 
-	luck, skillRobot, skillHuman, err := LoadLuck("luck.gob")
+	rules, err := pr.ParseRules(words.Alphabet, *rulesFlag)
 	if err != nil {
-		fmt.Println("loading luck failed", err)
+		fmt.Println(err)
 		os.Exit(1)
 	}
 
-	tips := make([]Tip, 0)
+	// luck.gob (first/) je spočítaný pro FoldBase, s jinými pravidly by štěstí
+	// a obtížnost 1. tahu neseděly
+	var luck map[string]*LuckStat
+	var skillRobot, skillHuman map[string]*odds.Skill
+	if rules.String() == pr.FoldBase().String() {
+		luck, skillRobot, skillHuman, err = LoadLuck("luck.gob")
+		if err != nil {
+			fmt.Println("loading luck failed", err)
+			os.Exit(1)
+		}
+	} else {
+		fmt.Printf("luck.gob platí jen pro -rules fold, 1. tah bude bez štěstí a obtížnosti\n\n")
+	}
+
+	size := words.Size
+	session := Session{Turn: 1, Tips: make([]Tip, 0), Progress: pr.NewProgress(size, words, rules)}
+	progress := session.Progress
+
+	if *sessionPath != "" {
+		err := LoadSession(*sessionPath, &session)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			fmt.Println("loading session failed", err)
+			os.Exit(1)
+		}
+		if err == nil && session.Turn > 1 {
+			fmt.Printf("Pokračuje se tahem č. %d\n", session.Turn)
+			counter, _, wordsLeft := progress.WordsLeft(true)
+			if counter < 1000 {
+				skillRobot, skillHuman = LiveOdds(wordsLeft, progress, luck, history)
+			}
+			fmt.Println("")
+		}
+	}
+
+	// sessionMu hlídá session (i progress); hlavní smyčka ho pouští jen
+	// během čekání na vstup, Ctrl+C proto session uloží mezi tahy, ne
+	// uprostřed Apply nebo AppendTip.
+	var sessionMu sync.Mutex
+	sessionMu.Lock()
 
 	interruptedCh := make(chan os.Signal, 1)
 	signal.Ignore(syscall.SIGINT)
@@ -227,99 +490,167 @@ func main() {
 
 	go func() {
 		<-interruptedCh
-		PrintResuts(tips)
+		sessionMu.Lock()
+		if *sessionPath != "" {
+			if err := SaveSession(*sessionPath, &session); err != nil {
+				fmt.Println("saving session failed", err)
+			}
+		}
+		PrintResuts(session.Tips)
 		os.Exit(0)
 	}()
 
-	progress := pr.NewProgress(size, words)
-
 	stdIn := bufio.NewReader(os.Stdin)
+	read := func() (string, error) {
+		sessionMu.Unlock()
+		defer sessionMu.Lock()
+		return readLine(stdIn)
+	}
 
-	for i := 1; i <= 6; i++ {
-		word := make([]rune, 5)
-		progress.ResetRound()
+	var undo, redo []state
+	current := func() state {
+		return state{
+			turn:       session.Turn,
+			snapshot:   progress.Snapshot(),
+			tips:       slices.Clone(session.Tips),
+			luck:       maps.Clone(luck),
+			skillRobot: skillRobot,
+			skillHuman: skillHuman,
+		}
+	}
+	restore := func(st state) {
+		session.Turn = st.turn
+		progress.Restore(st.snapshot)
+		session.Tips = st.tips
+		luck, skillRobot, skillHuman = st.luck, st.skillRobot, st.skillHuman
+	}
 
+turns:
+	for session.Turn <= 6 {
+		i := session.Turn
+		before := current()
 		fmt.Printf("Tah č. %d\n", i)
-		fmt.Printf("Slovo:\n")
-		for j := 0; j < size; j++ {
-			word[j], _, err = stdIn.ReadRune()
-			if err != nil {
-				fmt.Println("\n", err)
-				os.Exit(2)
+		var word string
+		for {
+			for {
+				fmt.Printf("Slovo (slovo! = i mimo slovník, ?slovo = proč není kandidát, summary, undo, redo):\n")
+				word, err = read()
+				if err != nil {
+					fmt.Println("\n", err)
+					os.Exit(2)
+				}
+				if explain, ok := strings.CutPrefix(word, "?"); ok {
+					PrintExplain(progress, strings.TrimSpace(explain))
+					continue
+				}
+				switch word {
+				case "summary":
+					PrintSummary(progress)
+					continue
+				case "undo":
+					if len(undo) == 0 {
+						fmt.Println("Není co vrátit")
+						continue
+					}
+					redo = append(redo, current())
+					restore(undo[len(undo)-1])
+					undo = undo[:len(undo)-1]
+					fmt.Println("")
+					continue turns
+				case "redo":
+					if len(redo) == 0 {
+						fmt.Println("Není co zopakovat")
+						continue
+					}
+					undo = append(undo, current())
+					restore(redo[len(redo)-1])
+					redo = redo[:len(redo)-1]
+					fmt.Println("")
+					continue turns
+				}
+				var force bool
+				word, force = strings.CutSuffix(word, "!")
+				word, err = words.Alphabet.Normalize(word)
+				var letterErr *dict.LetterError
+				if errors.As(err, &letterErr) {
+					fmt.Printf("Neznámé písmeno %q\n", letterErr.Letter)
+					continue
+				}
+				if utf8.RuneCountInString(word) != size {
+					fmt.Printf("Slovo musí mít %d písmen\n", size)
+					continue
+				}
+				// tip se zadává tak, jak ho ukáže hra (diakritika na zelených je
+				// z řešení), stačí tedy, aby ho pravidla nerozlišila od slova slovníku
+				variants := words.LookupBase(word)
+				base, _ := words.Alphabet.Strip(word) // Normalize ověřil písmena
+				typed := &dict.DictionaryWord{Word: word, WithoutDiacritics: base}
+				if force || slices.ContainsFunc(variants, func(v *dict.DictionaryWord) bool {
+					return rules.SameWord(v, typed)
+				}) {
+					break
+				}
+				if len(variants) > 0 {
+					fmt.Printf("Slovo %q není ve slovníku, myslíš %s?\n", word, variants[0].Word)
+				} else {
+					fmt.Printf("Slovo %q není ve slovníku (překlep?), pokud je správně, zadej %s!\n", word, word)
+				}
 			}
-		}
-		stdIn.ReadLine() // read end of line
-
-		fmt.Println("Označ zelenou(+), modrou(*), oranžovou(.) a šedou( ):")
-		for j := 0; j < size; j++ {
-			r, _, err := stdIn.ReadRune()
-			if err != nil {
-				fmt.Println("\n", err)
-				os.Exit(2)
+
+			var pattern pr.Pattern
+			for {
+				fmt.Println("Označ zelenou(+), modrou(*), oranžovou(.) a šedou( ):")
+				line, err := read()
+				if err != nil {
+					fmt.Println("\n", err)
+					os.Exit(2)
+				}
+				pattern, err = pr.ParsePatternN(line, size)
+				if err == nil {
+					break
+				}
+				fmt.Println(err)
 			}
 
-			switch r {
-			case ' ':
-				progress.Grey(j, word[j])
-			case '.':
-				progress.Orange(j, word[j])
-			case '*':
-				progress.GreenOrange(j, word[j])
-			case '+':
-				progress.Green(j, word[j])
-			default:
-				fmt.Println("\nInvalid character")
-				os.Exit(2)
+			progress.ResetRound()
+			if err := progress.Apply(word, pattern); err != nil {
+				fmt.Println(err)
+				fmt.Println("Zadej tah znovu")
+				continue
 			}
-			word[j] = dict.Conv[word[j]] // odstranění diakritiky
+			undo = append(undo, before)
+			redo = nil
+			break
 		}
-		stdIn.ReadLine() // read end of line
 
-		guessedWord := makeString(word)
+		guessedWord, _ := words.Alphabet.Strip(word)
 
 		counter, counterNotUsed, wordsLeft := progress.WordsLeft(true)
 
-		if counter == 1 && wordsLeft[0].WithoutDiacritics == guessedWord {
+		if counter == 1 && rules.SameWord(wordsLeft[0], &dict.DictionaryWord{Word: word, WithoutDiacritics: guessedWord}) {
 			counter = 0
 			counterNotUsed = 0
 		}
 
 		fmt.Printf("\nZbývá %d slov\n\n", counter)
 
-		tips = AppendTip(tips, guessedWord, counter, counterNotUsed, luck, skillRobot, skillHuman)
+		session.Tips = AppendTip(session.Tips, guessedWord, counter, counterNotUsed, luck, skillRobot, skillHuman)
+		session.Turn = i + 1
 
 		if counter == 0 {
-			break
+			break turns
 		}
 
 		if counter < 1000 {
 			// jinak se to počítá moc dlouho
-			wordsLeftRobotWeighted := make([]odds.WeightedWord, len(wordsLeft))
-			wordsLeftHumanWeighted := make([]odds.WeightedWord, len(wordsLeft))
-
-			for j, dw := range wordsLeft {
-				oddsHuman, oddsRobot, wordLuck := CalculateOdds(dw, wordsLeft, progress)
-				wordsLeftRobotWeighted[j] = odds.WeightedWord{dw.Word, oddsRobot}
-				wordsLeftHumanWeighted[j] = odds.WeightedWord{dw.Word, oddsHuman}
-				luck[dw.WithoutDiacritics] = wordLuck
-			}
-
-			sort.Sort(odds.ByWeight(wordsLeftHumanWeighted))
-			skillHuman = odds.CalculateSkill(wordsLeftHumanWeighted)
-
-			sort.Sort(odds.ByWeight(wordsLeftRobotWeighted))
-			skillRobot = odds.CalculateSkill(wordsLeftRobotWeighted)
-
-			for _, w := range wordsLeftRobotWeighted {
-				if history[w.Word] {
-					w.Word += " *** "
-				}
-				fmt.Printf("%s %f\n", w.Word, w.Weight)
-			}
+			skillRobot, skillHuman = LiveOdds(wordsLeft, progress, luck, history)
 		}
 
 		fmt.Println("")
 	}
 
-	PrintResuts(tips)
+	if *sessionPath != "" {
+		os.Remove(*sessionPath)
+	}
+	PrintResuts(session.Tips)
 }
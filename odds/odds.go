@@ -40,7 +40,7 @@ func CalculateSkill(words []WeightedWord) map[string]*Skill {
 		if diff > 0 {
 			sk = 100 - int(math.Round((w.Weight-minW)*diffInv))
 		}
-		w := dict.StripDiacritic(w.Word)
+		w, _ := dict.StripDiacritic(w.Word) // písmena mimo abecedu zůstanou
 		skill[w] = &Skill{Relative: sk, Difficulty: diff}
 	}
 
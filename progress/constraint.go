@@ -0,0 +1,261 @@
+package progress
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pracj3am/wordle-solver/dict"
+)
+
+// maxSurvivors = kolik slov nanejvýš ukáže ContradictionError.
+const maxSurvivors = 5
+
+type ConstraintKind int
+
+const (
+	Locked   ConstraintKind = iota // na pozici Pos je přesně písmeno Letter (s diakritikou)
+	Excluded                       // na pozici Pos není písmeno Letter (písmeno třídy podle Rules)
+	AtLeast                        // písmeno Letter (třídy) je ve slově aspoň Count×
+	Exactly                        // písmeno Letter (třídy) je ve slově přesně Count×
+)
+
+// Constraint = jedno omezení, na které se rozpadne tah. Pos platí pro
+// Locked/Excluded, Count pro AtLeast/Exactly.
+type Constraint struct {
+	Kind   ConstraintKind
+	Pos    int
+	Letter rune
+	Count  int
+}
+
+func (c Constraint) String() string {
+	switch c.Kind {
+	case Locked:
+		return fmt.Sprintf("position %d is %c", c.Pos+1, c.Letter)
+	case Excluded:
+		return fmt.Sprintf("position %d is not %c", c.Pos+1, c.Letter)
+	case AtLeast:
+		return fmt.Sprintf("at least %d× %c", c.Count, c.Letter)
+	default:
+		return fmt.Sprintf("exactly %d× %c", c.Count, c.Letter)
+	}
+}
+
+// ContradictionError = po tahu by nezbylo žádné slovo. Constraint je omezení,
+// po jehož přidání fond zůstal prázdný, Survivors (některá) slova, která
+// zbyla těsně před ním (po předchozích omezeních tahu).
+type ContradictionError struct {
+	Constraint Constraint
+	Survivors  []string
+}
+
+func (e *ContradictionError) Error() string {
+	msg := "no words left after: " + e.Constraint.String()
+	if len(e.Survivors) > 0 {
+		msg += " (without it: " + strings.Join(e.Survivors, ", ") + ")"
+	}
+	return msg
+}
+
+// turn rozloží tah na omezení: nejdřív pozice zleva, pak frekvence písmen
+// v pořadí jejich prvního výskytu v tipu. Zelená (na rozdíl od modré) říká
+// i to, že jinde už se písmeno ve slově nevyskytuje. Šedá, oranžová
+// a frekvence se týkají písmene třídy podle rules. Tip a vzor mají stejnou
+// délku (hlídá Apply).
+func turn(rules Rules, guess string, pat Pattern) []Constraint {
+	var cs []Constraint
+	var order []rune
+	freq := make(map[rune]*LetterFreq)
+
+	i := 0
+	for _, písmeno := range guess {
+		letter := rules.fold(písmeno)
+		f, ok := freq[letter]
+		if !ok {
+			f = new(LetterFreq)
+			freq[letter] = f
+			order = append(order, letter)
+		}
+		switch pat.At(i) {
+		case Green:
+			cs = append(cs, Constraint{Kind: Locked, Pos: i, Letter: písmeno})
+			f.f++
+			f.exact = true
+		case Blue:
+			cs = append(cs, Constraint{Kind: Locked, Pos: i, Letter: písmeno})
+			f.f += 2
+			f.floor = true
+		case Orange:
+			cs = append(cs, Constraint{Kind: Excluded, Pos: i, Letter: letter})
+			f.f++
+			f.floor = true
+		default:
+			cs = append(cs, Constraint{Kind: Excluded, Pos: i, Letter: letter})
+			f.exact = true
+		}
+		i++
+	}
+
+	for _, letter := range order {
+		f := freq[letter]
+		if f.exact {
+			cs = append(cs, Constraint{Kind: Exactly, Letter: letter, Count: f.f})
+		} else {
+			cs = append(cs, Constraint{Kind: AtLeast, Letter: letter, Count: f.f})
+		}
+	}
+
+	return cs
+}
+
+// add zapracuje omezení; písmeno mimo abecedu je chyba volajícího (viz class).
+func (p *Progress) add(c Constraint) {
+	index, _ := p.class(c.Letter)
+	switch c.Kind {
+	case Locked:
+		p.pos[c.Pos].solved = true
+		p.pos[c.Pos].písmeno = c.Letter
+	case Excluded:
+		if p.pos[c.Pos].left != nil {
+			p.pos[c.Pos].left[index] = false
+		}
+	case AtLeast:
+		p.freq[index].f += c.Count
+		p.freq[index].floor = true
+	case Exactly:
+		p.freq[index].f += c.Count
+		p.freq[index].exact = true
+	}
+}
+
+// contradiction sestaví chybu pro omezení cs[culprit], první, po kterém fond
+// zůstal prázdný: přehraje omezení před ním (fond tedy prázdný není).
+func (p *Progress) contradiction(cs []Constraint, culprit int) *ContradictionError {
+	q := p.Clone()
+	q.apply(cs[:culprit])
+	err := &ContradictionError{Constraint: cs[culprit]}
+	q.walk(func(dw *dict.DictionaryWord) bool {
+		err.Survivors = append(err.Survivors, dw.Word)
+		return len(err.Survivors) < maxSurvivors
+	})
+	return err
+}
+
+// Violation = omezení, které slovo porušuje. Písmeno je, co má slovo na
+// pozici (Locked/Excluded), Count kolikrát obsahuje písmeno (AtLeast/Exactly).
+// AccentOnly = zelená pozice nesedí jen kvůli diakritice.
+type Violation struct {
+	Constraint Constraint
+	Písmeno    rune
+	Count      int
+	AccentOnly bool
+}
+
+func (v Violation) String() string {
+	c := v.Constraint
+	switch c.Kind {
+	case Locked:
+		if v.AccentOnly {
+			return fmt.Sprintf("position %d is %c, not %c (accent)", c.Pos+1, c.Letter, v.Písmeno)
+		}
+		return fmt.Sprintf("position %d is %c, not %c", c.Pos+1, c.Letter, v.Písmeno)
+	case Excluded:
+		return fmt.Sprintf("position %d cannot be %c", c.Pos+1, v.Písmeno)
+	case AtLeast:
+		return fmt.Sprintf("needs at least %d× %c, has %d", c.Count, c.Letter, v.Count)
+	default:
+		return fmt.Sprintf("needs exactly %d× %c, has %d", c.Count, c.Letter, v.Count)
+	}
+}
+
+// Explain vrátí omezení, která slovo word porušuje (prázdné = slovo omezením
+// vyhovuje). Slovo se převede na dict.Canonical.
+func (p *Progress) Explain(word string) ([]Violation, error) {
+	word = dict.Canonical(word)
+	indexes := make([]int, 0, len(p.pos))
+	for _, písmeno := range word {
+		index, ok := p.rules.index(písmeno)
+		if !ok {
+			return nil, fmt.Errorf("explain %q: unknown letter %q", word, písmeno)
+		}
+		indexes = append(indexes, index)
+	}
+	if len(indexes) != len(p.pos) {
+		return nil, fmt.Errorf("explain %q: %d letters, want %d", word, len(indexes), len(p.pos))
+	}
+
+	a := p.Alphabet()
+	var vs []Violation
+	for i, index := range indexes {
+		pp := &p.pos[i]
+		písmeno := a.Písmena[index]
+		if pp.solved && pp.písmeno != písmeno {
+			vs = append(vs, Violation{
+				Constraint: Constraint{Kind: Locked, Pos: i, Letter: pp.písmeno},
+				Písmeno:    písmeno,
+				AccentOnly: a.Conv[pp.písmeno] == a.Conv[písmeno],
+			})
+		}
+		if !pp.solved && !pp.left[p.rules.classOf(index)] {
+			vs = append(vs, Violation{
+				Constraint: Constraint{Kind: Excluded, Pos: i, Letter: p.rules.fold(písmeno)},
+				Písmeno:    písmeno,
+			})
+		}
+	}
+
+	for i, fs := range p.bounds() {
+		n := freq(p.rules.table, i, indexes)
+		for _, f := range fs {
+			if f.isExact() {
+				if f.f != n {
+					vs = append(vs, Violation{Constraint: Constraint{Kind: Exactly, Letter: p.rules.letter(i), Count: f.f}, Count: n})
+				}
+			} else if f.floor && f.f > n {
+				vs = append(vs, Violation{Constraint: Constraint{Kind: AtLeast, Letter: p.rules.letter(i), Count: f.f}, Count: n})
+			}
+		}
+	}
+
+	return vs, nil
+}
+
+// HardModeError = tip nepoužívá všechny odkryté nápovědy (zelené s přesnou
+// diakritikou, oranžové/modré v potřebném počtu).
+type HardModeError struct {
+	Violations []Violation
+}
+
+func (e *HardModeError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.String()
+	}
+	return "hard mode: " + strings.Join(msgs, "; ")
+}
+
+// CheckHardMode ověří, že by tip guess byl v hard mode povolený. Nápovědy bere
+// ze všech dosavadních kol (zamčené pozice a frekvence včetně past).
+func (p *Progress) CheckHardMode(guess string) error {
+	vs, err := p.Explain(guess)
+	if err != nil {
+		return err
+	}
+
+	var hard []Violation
+	for _, v := range vs {
+		switch v.Constraint.Kind {
+		case Locked, AtLeast:
+			hard = append(hard, v)
+		case Exactly:
+			if v.Count < v.Constraint.Count {
+				v.Constraint.Kind = AtLeast
+				hard = append(hard, v)
+			}
+		}
+	}
+	if len(hard) > 0 {
+		return &HardModeError{Violations: hard}
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package progress
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pracj3am/wordle-solver/dict"
+)
+
+func TestApplyContradiction(t *testing.T) {
+	d := testDictionary(t)
+	tests := []struct {
+		guess      string
+		pat        Pattern
+		constraint Constraint
+		survivors  []string
+	}{
+		{"barva", "+    ", Constraint{Kind: Excluded, Pos: 1, Letter: 'a'}, []string{"barva"}},
+		{"krava", "     ", Constraint{Kind: Exactly, Letter: 'k'}, []string{"mleko", "pivko"}},
+		{"KRÁVA", "++.++", Constraint{Kind: Excluded, Pos: 2, Letter: 'a'}, []string{"krava", "kráva"}},
+	}
+	for _, tt := range tests {
+		p := NewProgress(d.Size, d, FoldBase())
+		before := p.Snapshot()
+		err := p.Apply(tt.guess, tt.pat)
+		var cerr *ContradictionError
+		if !errors.As(err, &cerr) {
+			t.Errorf("Apply(%q, %q) = %v, want *ContradictionError", tt.guess, tt.pat, err)
+			continue
+		}
+		if cerr.Constraint != tt.constraint || !slices.Equal(cerr.Survivors, tt.survivors) {
+			t.Errorf("Apply(%q, %q) = %v %q, want %v %q", tt.guess, tt.pat, cerr.Constraint, cerr.Survivors, tt.constraint, tt.survivors)
+		}
+		if !reflect.DeepEqual(p.Snapshot(), before) {
+			t.Errorf("Apply(%q, %q) changed the progress", tt.guess, tt.pat)
+		}
+	}
+}
+
+func TestGuessUnknownLetter(t *testing.T) {
+	d := testDictionary(t)
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Guess with a letter outside the alphabet: want panic")
+		}
+	}()
+	NewProgress(d.Size, d, FoldBase()).Guess("kr1va", "krava")
+}
+
+func TestExplain(t *testing.T) {
+	d := testDictionary(t)
+	p := NewProgress(d.Size, d, FoldBase())
+	if err := p.Apply("barva", ScoreGuess("barva", "kráva")); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		word string
+		want []Violation
+	}{
+		{"kráva", nil},
+		{"KRAVA", nil},
+		{"mleko", []Violation{
+			{Constraint: Constraint{Kind: Locked, Pos: 3, Letter: 'v'}, Písmeno: 'k'},
+			{Constraint: Constraint{Kind: Locked, Pos: 4, Letter: 'a'}, Písmeno: 'o'},
+			{Constraint: Constraint{Kind: Exactly, Letter: 'a', Count: 2}},
+			{Constraint: Constraint{Kind: AtLeast, Letter: 'r', Count: 1}},
+			{Constraint: Constraint{Kind: Exactly, Letter: 'v', Count: 1}},
+		}},
+		{"krává", []Violation{
+			{Constraint: Constraint{Kind: Locked, Pos: 4, Letter: 'a'}, Písmeno: 'á', AccentOnly: true},
+		}},
+		{"brrva", []Violation{
+			{Constraint: Constraint{Kind: Excluded, Pos: 0, Letter: 'b'}, Písmeno: 'b'},
+			{Constraint: Constraint{Kind: Excluded, Pos: 2, Letter: 'r'}, Písmeno: 'r'},
+			{Constraint: Constraint{Kind: Exactly, Letter: 'a', Count: 2}, Count: 1},
+			{Constraint: Constraint{Kind: Exactly, Letter: 'b'}, Count: 1},
+		}},
+	}
+	for _, tt := range tests {
+		got, err := p.Explain(tt.word)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Explain(%q) = %v, %v, want %v", tt.word, got, err, tt.want)
+		}
+	}
+	for _, word := range []string{"kr1va", "krav", "kravaa"} {
+		if _, err := p.Explain(word); err == nil {
+			t.Errorf("Explain(%q): want error", word)
+		}
+	}
+}
+
+func TestCheckHardMode(t *testing.T) {
+	d := testDictionary(t)
+	p := NewProgress(d.Size, d, FoldBase())
+	if err := p.Apply("barva", ScoreGuess("barva", "kráva")); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		guess string
+		want  []Violation // nil = tip je povolený
+	}{
+		{"kráva", nil},
+		{"krava", nil},
+		// šedá písmena a přebytečné výskyty hard mode nevadí
+		{"brrva", []Violation{
+			{Constraint: Constraint{Kind: AtLeast, Letter: 'a', Count: 2}, Count: 1},
+		}},
+		{"krává", []Violation{
+			{Constraint: Constraint{Kind: Locked, Pos: 4, Letter: 'a'}, Písmeno: 'á', AccentOnly: true},
+		}},
+		{"mleko", []Violation{
+			{Constraint: Constraint{Kind: Locked, Pos: 3, Letter: 'v'}, Písmeno: 'k'},
+			{Constraint: Constraint{Kind: Locked, Pos: 4, Letter: 'a'}, Písmeno: 'o'},
+			{Constraint: Constraint{Kind: AtLeast, Letter: 'a', Count: 2}},
+			{Constraint: Constraint{Kind: AtLeast, Letter: 'r', Count: 1}},
+			{Constraint: Constraint{Kind: AtLeast, Letter: 'v', Count: 1}},
+		}},
+	}
+	check := func(p *Progress) {
+		t.Helper()
+		for _, tt := range tests {
+			err := p.CheckHardMode(tt.guess)
+			var herr *HardModeError
+			switch {
+			case tt.want == nil && err != nil:
+				t.Errorf("CheckHardMode(%q) = %v, want nil", tt.guess, err)
+			case tt.want != nil && !errors.As(err, &herr):
+				t.Errorf("CheckHardMode(%q) = %v, want *HardModeError", tt.guess, err)
+			case tt.want != nil && !reflect.DeepEqual(herr.Violations, tt.want):
+				t.Errorf("CheckHardMode(%q) = %v, want %v", tt.guess, herr.Violations, tt.want)
+			}
+		}
+	}
+	check(p)
+
+	// nápovědy minulých kol platí dál
+	p.ResetRound()
+	if err := p.Apply("hosty", ScoreGuess("hosty", "kráva")); err != nil {
+		t.Fatal(err)
+	}
+	check(p)
+
+	var herr *HardModeError
+	if err := p.CheckHardMode("kr1va"); err == nil || errors.As(err, &herr) {
+		t.Errorf("CheckHardMode(%q) = %v, want a non-hard-mode error", "kr1va", err)
+	}
+}
+
+// matches = slovo vyhoví výrazu i omezením z Regexp.
+func matches(p *Progress, word string) bool {
+	re, cs := p.Regexp()
+	if !re.MatchString(word) {
+		return false
+	}
+	for _, c := range cs {
+		n := 0
+		for _, písmeno := range word {
+			if p.rules.fold(písmeno) == c.Letter {
+				n++
+			}
+		}
+		if c.Kind == Exactly && n != c.Count || c.Kind == AtLeast && n < c.Count {
+			return false
+		}
+	}
+	return true
+}
+
+func TestExplainAgreesWithWords(t *testing.T) {
+	d, err := dict.LoadDictionaryFromReader(strings.NewReader(partitionWords), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		rounds [][2]string // (tip, řešení) na kolo
+	}{
+		{"new", nil},
+		{"one round", [][2]string{{"barva", "kráva"}}},
+		{"two rounds", [][2]string{{"mleko", "hraná"}, {"vrána", "hraná"}}},
+		// kola si odporují: podle 1. kola a, r, v ve slově nejsou
+		{"contradiction", [][2]string{{"barva", "mleko"}, {"krava", "krava"}}},
+		{"accents", [][2]string{{"babka", "bábka"}, {"kosti", "kostí"}}},
+	}
+	for _, rules := range []Rules{FoldBase(), StrictAccents()} {
+		for _, tt := range tests {
+			p := NewProgress(d.Size, d, rules)
+			for _, r := range tt.rounds {
+				p.ResetRound()
+				p.Guess(r[0], r[1])
+			}
+			left := make(map[string]bool)
+			for dw := range p.Words() {
+				left[dw.Word] = true
+			}
+			for _, word := range strings.Fields(partitionWords) {
+				vs, err := p.Explain(word)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if (len(vs) == 0) != left[word] {
+					t.Errorf("%v %s: Explain(%q) = %v, in Words %v", rules, tt.name, word, vs, left[word])
+				}
+				if matches(p, word) != left[word] {
+					t.Errorf("%v %s: Regexp matches %q: %v, in Words %v", rules, tt.name, word, !left[word], left[word])
+				}
+			}
+		}
+	}
+}
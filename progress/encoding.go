@@ -0,0 +1,341 @@
+package progress
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// encodingVersion = verze formátu JSON i binárního zápisu Progress.
+const encodingVersion = 1
+
+type jsonPosition struct {
+	Solved  string  `json:"solved,omitempty"`  // zamčené písmeno (s diakritikou)
+	Allowed *string `json:"allowed,omitempty"` // povolená písmena tříd
+}
+
+type jsonFreq struct {
+	Letter string `json:"letter"`
+	Count  int    `json:"count"`
+	Floor  bool   `json:"floor,omitempty"`
+	Exact  bool   `json:"exact,omitempty"`
+}
+
+type jsonProgress struct {
+	Version   int            `json:"version"`
+	Rules     string         `json:"rules"`
+	Positions []jsonPosition `json:"positions"`
+	Freq      []jsonFreq     `json:"freq"`
+	Past      []jsonFreq     `json:"past,omitempty"` // frekvence minulých kol
+}
+
+// MarshalJSON uloží stav omezení (bez slovníku).
+func (p *Progress) MarshalJSON() ([]byte, error) {
+	jp := jsonProgress{Version: encodingVersion, Rules: p.rules.String(), Positions: make([]jsonPosition, len(p.pos)), Freq: p.jsonFreqs(p.freq)}
+	if past := p.jsonFreqs(p.past); len(past) > 0 {
+		jp.Past = past
+	}
+	for i, pp := range p.pos {
+		if pp.solved {
+			jp.Positions[i].Solved = string(pp.písmeno)
+		}
+		if pp.left != nil {
+			var b strings.Builder
+			for index, ok := range pp.left {
+				if ok {
+					b.WriteRune(p.rules.letter(index))
+				}
+			}
+			allowed := b.String()
+			jp.Positions[i].Allowed = &allowed
+		}
+	}
+	return json.Marshal(jp)
+}
+
+// jsonFreqs vrátí nenulové frekvence pro JSON.
+func (p *Progress) jsonFreqs(freq []LetterFreq) []jsonFreq {
+	jfs := []jsonFreq{}
+	for index, f := range freq {
+		if f != (LetterFreq{}) {
+			jfs = append(jfs, jsonFreq{Letter: string(p.rules.letter(index)), Count: f.f, Floor: f.floor, Exact: f.exact})
+		}
+	}
+	return jfs
+}
+
+// freqsFromJSON je opak jsonFreqs.
+func (p *Progress) freqsFromJSON(jfs []jsonFreq) ([]LetterFreq, error) {
+	freq := make([]LetterFreq, p.rules.size())
+	for _, jf := range jfs {
+		letter := []rune(jf.Letter)
+		if len(letter) != 1 {
+			return nil, fmt.Errorf("progress: invalid letter %q", jf.Letter)
+		}
+		class, ok := p.rules.classOfLetter(letter[0])
+		if !ok {
+			return nil, fmt.Errorf("progress: invalid letter %q", jf.Letter)
+		}
+		freq[class] = LetterFreq{f: jf.Count, floor: jf.Floor, exact: jf.Exact}
+	}
+	return freq, nil
+}
+
+// UnmarshalJSON obnoví stav omezení. Slovník ani pravidla se nemění, takže se
+// má obnovovat do Progress z NewProgress (se stejnou délkou slov a pravidly).
+func (p *Progress) UnmarshalJSON(data []byte) error {
+	var jp jsonProgress
+	if err := json.Unmarshal(data, &jp); err != nil {
+		return err
+	}
+	if jp.Version != encodingVersion {
+		return fmt.Errorf("progress: unsupported version %d", jp.Version)
+	}
+	if err := p.checkRules(jp.Rules); err != nil {
+		return err
+	}
+	if err := p.checkSize(len(jp.Positions)); err != nil {
+		return err
+	}
+
+	pos := make([]PositionProgress, len(jp.Positions))
+	for i, jpp := range jp.Positions {
+		if jpp.Solved != "" {
+			písmeno := []rune(jpp.Solved)
+			if len(písmeno) != 1 || !p.known(písmeno[0]) {
+				return fmt.Errorf("progress: position %d: invalid letter %q", i+1, jpp.Solved)
+			}
+			pos[i].solved = true
+			pos[i].písmeno = písmeno[0]
+		}
+		if jpp.Allowed != nil {
+			pos[i].left = make([]bool, p.rules.size())
+			for _, letter := range *jpp.Allowed {
+				class, ok := p.rules.classOfLetter(letter)
+				if !ok {
+					return fmt.Errorf("progress: position %d: invalid letter %q", i+1, letter)
+				}
+				pos[i].left[class] = true
+			}
+		} else if !pos[i].solved {
+			return fmt.Errorf("progress: position %d: neither solved nor allowed", i+1)
+		}
+	}
+
+	freq, err := p.freqsFromJSON(jp.Freq)
+	if err != nil {
+		return err
+	}
+	past, err := p.freqsFromJSON(jp.Past)
+	if err != nil {
+		return err
+	}
+
+	p.pos, p.freq, p.past = pos, freq, past
+	return nil
+}
+
+const (
+	binSolved = 1 << iota
+	binAllowed
+)
+
+const (
+	binFloor = 1 << iota
+	binExact
+)
+
+// MarshalBinary je kompaktní obdoba MarshalJSON: verze, pravidla, pozice
+// (příznaky, zamčené písmeno, bitová maska povolených tříd) a nenulové
+// frekvence kola a minulých kol.
+func (p *Progress) MarshalBinary() ([]byte, error) {
+	if p.rules.size() > 64 {
+		return nil, fmt.Errorf("progress: %d letters do not fit a binary mask", p.rules.size())
+	}
+	b := []byte{encodingVersion}
+	rules := p.rules.String()
+	b = binary.AppendUvarint(b, uint64(len(rules)))
+	b = append(b, rules...)
+	b = binary.AppendUvarint(b, uint64(len(p.pos)))
+	for _, pp := range p.pos {
+		var flags byte
+		if pp.solved {
+			flags |= binSolved
+		}
+		if pp.left != nil {
+			flags |= binAllowed
+		}
+		b = append(b, flags)
+		if pp.solved {
+			b = binary.AppendUvarint(b, uint64(pp.písmeno))
+		}
+		if pp.left != nil {
+			var mask uint64
+			for index, ok := range pp.left {
+				if ok {
+					mask |= 1 << index
+				}
+			}
+			b = binary.LittleEndian.AppendUint64(b, mask)
+		}
+	}
+
+	b = appendFreqs(b, p.freq)
+	b = appendFreqs(b, p.past)
+	return b, nil
+}
+
+// appendFreqs zapíše počet nenulových frekvencí a pak každou jako index
+// třídy, příznaky a počet.
+func appendFreqs(b []byte, freq []LetterFreq) []byte {
+	var n int
+	for _, f := range freq {
+		if f != (LetterFreq{}) {
+			n++
+		}
+	}
+	b = binary.AppendUvarint(b, uint64(n))
+	for index, f := range freq {
+		if f == (LetterFreq{}) {
+			continue
+		}
+		var flags byte
+		if f.floor {
+			flags |= binFloor
+		}
+		if f.exact {
+			flags |= binExact
+		}
+		b = append(b, byte(index), flags)
+		b = binary.AppendUvarint(b, uint64(f.f))
+	}
+	return b
+}
+
+var errBinary = errors.New("progress: truncated or corrupt binary data")
+
+// UnmarshalBinary obnoví stav z MarshalBinary (viz UnmarshalJSON).
+func (p *Progress) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return errBinary
+	}
+	version := data[0]
+	if version != encodingVersion {
+		return fmt.Errorf("progress: unsupported version %d", version)
+	}
+	data = data[1:]
+
+	uvarint := func() (uint64, bool) {
+		v, n := binary.Uvarint(data)
+		if n <= 0 {
+			return 0, false
+		}
+		data = data[n:]
+		return v, true
+	}
+
+	n, ok := uvarint()
+	if !ok || n > uint64(len(data)) {
+		return errBinary
+	}
+	rules := string(data[:n])
+	data = data[n:]
+	if err := p.checkRules(rules); err != nil {
+		return err
+	}
+
+	size, ok := uvarint()
+	if !ok || size > uint64(len(data)) {
+		return errBinary
+	}
+	if err := p.checkSize(int(size)); err != nil {
+		return err
+	}
+
+	pos := make([]PositionProgress, size)
+	for i := range pos {
+		if len(data) == 0 {
+			return errBinary
+		}
+		flags := data[0]
+		data = data[1:]
+		if flags&binSolved != 0 {
+			r, ok := uvarint()
+			if !ok {
+				return errBinary
+			}
+			if !p.known(rune(r)) {
+				return fmt.Errorf("progress: position %d: invalid letter %q", i+1, rune(r))
+			}
+			pos[i].solved = true
+			pos[i].písmeno = rune(r)
+		}
+		if flags&binAllowed != 0 {
+			if len(data) < 8 {
+				return errBinary
+			}
+			mask := binary.LittleEndian.Uint64(data)
+			data = data[8:]
+			pos[i].left = make([]bool, p.rules.size())
+			for index := range pos[i].left {
+				pos[i].left[index] = mask&(1<<index) != 0
+			}
+		} else if flags&binSolved == 0 {
+			return fmt.Errorf("progress: position %d: neither solved nor allowed", i+1)
+		}
+	}
+
+	readFreqs := func() ([]LetterFreq, bool) {
+		freq := make([]LetterFreq, p.rules.size())
+		n, ok := uvarint()
+		if !ok {
+			return nil, false
+		}
+		for ; n > 0; n-- {
+			if len(data) < 2 || int(data[0]) >= len(freq) {
+				return nil, false
+			}
+			index, flags := data[0], data[1]
+			data = data[2:]
+			f, ok := uvarint()
+			if !ok {
+				return nil, false
+			}
+			freq[index] = LetterFreq{f: int(f), floor: flags&binFloor != 0, exact: flags&binExact != 0}
+		}
+		return freq, true
+	}
+	freq, ok := readFreqs()
+	if !ok {
+		return errBinary
+	}
+	past, ok := readFreqs()
+	if !ok || len(data) != 0 {
+		return errBinary
+	}
+
+	p.pos, p.freq, p.past = pos, freq, past
+	return nil
+}
+
+// known = písmeno je v abecedě.
+func (p *Progress) known(písmeno rune) bool {
+	_, ok := p.rules.index(písmeno)
+	return ok
+}
+
+func (p *Progress) checkRules(rules string) error {
+	if rules != p.rules.String() {
+		return fmt.Errorf("progress: rules %q, want %q", rules, p.rules.String())
+	}
+	return nil
+}
+
+func (p *Progress) checkSize(size int) error {
+	if p.words != nil && p.words.Size != size {
+		return fmt.Errorf("progress: %d positions, dictionary has %d-letter words", size, p.words.Size)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package progress
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pracj3am/wordle-solver/dict"
+)
+
+// testProgress vrátí stav po tazích (tip, řešení); mezi tahy volá ResetRound.
+func testProgress(t *testing.T, d *dict.Dictionary, rules Rules, moves ...[2]string) *Progress {
+	t.Helper()
+	p := NewProgress(d.Size, d, rules)
+	for _, m := range moves {
+		p.ResetRound()
+		if err := p.Apply(m[0], rules.ScoreGuess(m[0], m[1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return p
+}
+
+func TestEncodingRoundTrip(t *testing.T) {
+	d := testDictionary(t)
+	strict := StrictAccents()
+	tests := []struct {
+		name  string
+		rules Rules
+		moves [][2]string
+	}{
+		{"new", FoldBase(), nil},
+		{"one round", FoldBase(), [][2]string{{"barva", "kráva"}}},
+		{"two rounds", FoldBase(), [][2]string{{"pivko", "kráva"}, {"barva", "kráva"}}},
+		{"strict", strict, [][2]string{{"vrána", "kráva"}, {"krava", "kráva"}}},
+	}
+	for _, tt := range tests {
+		p := testProgress(t, d, tt.rules, tt.moves...)
+		want := p.Snapshot()
+
+		data, err := json.Marshal(p)
+		if err != nil {
+			t.Fatalf("%s: MarshalJSON: %v", tt.name, err)
+		}
+		q := NewProgress(d.Size, d, tt.rules)
+		if err := json.Unmarshal(data, q); err != nil {
+			t.Errorf("%s: UnmarshalJSON(%s): %v", tt.name, data, err)
+		} else if got := q.Snapshot(); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: JSON round trip: %+v, want %+v", tt.name, got, want)
+		}
+
+		bin, err := p.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%s: MarshalBinary: %v", tt.name, err)
+		}
+		q = NewProgress(d.Size, d, tt.rules)
+		if err := q.UnmarshalBinary(bin); err != nil {
+			t.Errorf("%s: UnmarshalBinary(%x): %v", tt.name, bin, err)
+		} else if got := q.Snapshot(); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: binary round trip: %+v, want %+v", tt.name, got, want)
+		}
+	}
+}
+
+func TestEncodingMismatch(t *testing.T) {
+	d := testDictionary(t)
+	p := testProgress(t, d, FoldBase(), [2]string{"barva", "kráva"})
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := json.Unmarshal(data, NewProgress(d.Size, d, StrictAccents())); err == nil {
+		t.Errorf("UnmarshalJSON with other rules: want error")
+	}
+	if err := NewProgress(d.Size, d, StrictAccents()).UnmarshalBinary(bin); err == nil {
+		t.Errorf("UnmarshalBinary with other rules: want error")
+	}
+
+	other, err := dict.LoadDictionaryFromReader(strings.NewReader("pes\nkos\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, NewProgress(other.Size, other, FoldBase())); err == nil {
+		t.Errorf("UnmarshalJSON into %d-letter dictionary: want error", other.Size)
+	}
+	if err := NewProgress(other.Size, other, FoldBase()).UnmarshalBinary(bin); err == nil {
+		t.Errorf("UnmarshalBinary into %d-letter dictionary: want error", other.Size)
+	}
+
+	future := strings.Replace(string(data), `"version":1`, `"version":2`, 1)
+	if err := json.Unmarshal([]byte(future), NewProgress(d.Size, d, FoldBase())); err == nil {
+		t.Errorf("UnmarshalJSON(%s): want error", future)
+	}
+	for n := range len(bin) {
+		if err := NewProgress(d.Size, d, FoldBase()).UnmarshalBinary(bin[:n]); err == nil {
+			t.Errorf("UnmarshalBinary(%x): want error", bin[:n])
+		}
+	}
+}
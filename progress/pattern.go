@@ -0,0 +1,268 @@
+package progress
+
+import (
+	"fmt"
+
+	"github.com/pracj3am/wordle-solver/dict"
+)
+
+// Color = barva zpětné vazby na jedné pozici; hodnota je zároveň znak, kterým
+// se barva zadává v CLI.
+type Color byte
+
+const (
+	Grey   Color = ' '
+	Orange Color = '.'
+	Blue   Color = '*' // zelená + písmeno je ve slově ještě jinde
+	Green  Color = '+'
+)
+
+// String vrátí název barvy (grey, orange, blue, green).
+func (c Color) String() string {
+	switch c {
+	case Grey:
+		return "grey"
+	case Orange:
+		return "orange"
+	case Blue:
+		return "blue"
+	case Green:
+		return "green"
+	}
+	return fmt.Sprintf("Color(%q)", byte(c))
+}
+
+// Pattern = zpětná vazba na celý tip, jedna Color na pozici. Je to hodnota
+// (dá se porovnávat a použít jako klíč mapy).
+type Pattern string
+
+func NewPattern(colors ...Color) Pattern {
+	b := make([]byte, len(colors))
+	for i, c := range colors {
+		b[i] = byte(c)
+	}
+	return Pattern(b)
+}
+
+func (pat Pattern) Len() int {
+	return len(pat)
+}
+
+func (pat Pattern) At(i int) Color {
+	return Color(pat[i])
+}
+
+// hasGreen = aspoň jedna pozice je zelená nebo modrá.
+func (pat Pattern) hasGreen() bool {
+	for i := range pat.Len() {
+		if c := pat.At(i); c == Green || c == Blue {
+			return true
+		}
+	}
+	return false
+}
+
+// String vrátí vzor ve znacích CLI (+ * . a mezera).
+func (pat Pattern) String() string {
+	return string(pat)
+}
+
+// symbols = všechny přijímané zápisy barev.
+var symbols = map[rune]Color{
+	' ': Grey,
+	'.': Orange,
+	'*': Blue,
+	'+': Green,
+	'x': Grey,
+	'X': Grey,
+	'y': Orange,
+	'Y': Orange,
+	'b': Blue,
+	'B': Blue,
+	'g': Green,
+	'G': Green,
+	'⬜': Grey,
+	'⬛': Grey,
+	'🟨': Orange,
+	'🟦': Blue,
+	'🟩': Green,
+}
+
+// PatternError = chyba při čtení vzoru; Pos je pozice od 0, -1 = vzor jako celek.
+type PatternError struct {
+	Pos  int
+	Char rune
+	Msg  string
+}
+
+func (e *PatternError) Error() string {
+	if e.Pos < 0 {
+		return "pattern: " + e.Msg
+	}
+	return fmt.Sprintf("pattern: position %d: %q: %s", e.Pos+1, e.Char, e.Msg)
+}
+
+// ParsePattern přečte vzor zapsaný znaky CLI (+ * . mezera), písmeny g/b/y/x
+// nebo emoji 🟩🟦🟨⬜.
+func ParsePattern(s string) (Pattern, error) {
+	colors := make([]Color, 0, len(s))
+	for _, r := range s {
+		if r == '\uFE0F' { // variation selector za ⬜ apod.
+			continue
+		}
+		c, ok := symbols[r]
+		if !ok {
+			err := &PatternError{Pos: len(colors), Char: r, Msg: "unknown symbol"}
+			switch r {
+			case '\u00A0':
+				err.Msg = "non-breaking space instead of a space"
+			case '\t':
+				err.Msg = "tab instead of a space"
+			}
+			return "", err
+		}
+		colors = append(colors, c)
+	}
+	if len(colors) == 0 {
+		return "", &PatternError{Pos: -1, Msg: "empty"}
+	}
+	return NewPattern(colors...), nil
+}
+
+// ParsePatternN je ParsePattern, který navíc vyžaduje přesně n pozic.
+func ParsePatternN(s string, n int) (Pattern, error) {
+	pat, err := ParsePattern(s)
+	if err != nil {
+		return "", err
+	}
+	if pat.Len() != n {
+		return "", &PatternError{Pos: -1, Msg: fmt.Sprintf("%d positions, want %d", pat.Len(), n)}
+	}
+	return pat, nil
+}
+
+// ScoreGuess spočítá zpětnou vazbu, kterou dostane tip guess proti řešení
+// solution. Zelená se porovnává na základním písmenu; každé písmeno tipu
+// (zleva, i zelené) pak spotřebuje první dosud nespárovaný výskyt v řešení —
+// zelené se tím změní na modrou, ostatní na oranžovou. Pro jiná pravidla
+// viz Rules.ScoreGuess.
+func ScoreGuess(guess, solution string) Pattern {
+	return FoldBase().ScoreGuess(guess, solution)
+}
+
+// scorer = ScoreGuess pro jeden tip a mnoho řešení (bez alokací na řešení
+// kromě výsledného vzoru).
+type scorer struct {
+	rules  Rules
+	ltrs   []rune // tip, písmena tříd podle rules
+	sol    []rune // řešení, písmena tříd podle rules
+	pat    []byte
+	green  []bool
+	paired []bool
+}
+
+func newScorer(rules Rules, guess string) *scorer {
+	sc := scorer{rules: rules}
+	for _, písmeno := range guess {
+		sc.ltrs = append(sc.ltrs, rules.fold(písmeno))
+	}
+	sc.pat = make([]byte, len(sc.ltrs))
+	return &sc
+}
+
+func (sc *scorer) score(solution string) Pattern {
+	sc.sol = sc.sol[:0]
+	for _, písmeno := range solution {
+		sc.sol = append(sc.sol, sc.rules.fold(písmeno))
+	}
+	sc.green = append(sc.green[:0], make([]bool, len(sc.sol))...)
+	sc.paired = append(sc.paired[:0], make([]bool, len(sc.sol))...)
+
+	for i, r := range sc.ltrs {
+		sc.pat[i] = byte(Grey)
+		if i < len(sc.sol) && r == sc.sol[i] {
+			sc.pat[i] = byte(Green)
+			sc.green[i] = true
+		}
+	}
+
+	for i, r := range sc.ltrs {
+		for j, s := range sc.sol {
+			if sc.green[j] || sc.paired[j] || s != r {
+				continue
+			}
+			sc.paired[j] = true
+			if sc.pat[i] == byte(Green) {
+				sc.pat[i] = byte(Blue)
+			} else {
+				sc.pat[i] = byte(Orange)
+			}
+			break
+		}
+	}
+
+	return Pattern(sc.pat)
+}
+
+// Partition rozdělí kandidáty podle zpětné vazby, kterou by dostal tip guess,
+// kdyby daný kandidát byl řešením (podle pravidel p). Diakritiku, kterou hra
+// odkryje na zelených, vzor nenese — skupinu podle ní dál dělí SplitRevealed.
+func (p *Progress) Partition(guess string, candidates []*dict.DictionaryWord) map[Pattern][]*dict.DictionaryWord {
+	sc := newScorer(p.rules, guess)
+	buckets := make(map[Pattern][]*dict.DictionaryWord)
+	for _, dw := range candidates {
+		pat := sc.score(dw.Word)
+		buckets[pat] = append(buckets[pat], dw)
+	}
+	return buckets
+}
+
+// SplitRevealed rozdělí skupinu z Partition (se vzorem pat) podle písmen,
+// která hra odkryje na zelených a modrých pozicích. Kandidáti z jedné výsledné
+// skupiny zůstanou po tahu všichni (a jiní ne).
+func SplitRevealed(pat Pattern, bucket []*dict.DictionaryWord) [][]*dict.DictionaryWord {
+	if len(bucket) < 2 || !pat.hasGreen() {
+		return [][]*dict.DictionaryWord{bucket}
+	}
+
+	groups := make(map[string][]*dict.DictionaryWord)
+	var order []string
+	revealed := make([]rune, 0, pat.Len())
+	for _, dw := range bucket {
+		revealed = revealed[:0]
+		i := 0
+		for _, písmeno := range dw.Word {
+			if i < pat.Len() && (pat.At(i) == Green || pat.At(i) == Blue) {
+				revealed = append(revealed, písmeno)
+			}
+			i++
+		}
+		key := string(revealed)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], dw)
+	}
+
+	if len(order) == 1 {
+		return [][]*dict.DictionaryWord{bucket}
+	}
+	split := make([][]*dict.DictionaryWord, len(order))
+	for i, key := range order {
+		split[i] = groups[key]
+	}
+	return split
+}
+
+// Reveal vrátí tip tak, jak ho ukáže hra: na zelených a modrých pozicích je
+// písmeno řešení (s jeho diakritikou).
+func Reveal(guess, solution string, pat Pattern) string {
+	sol := []rune(solution)
+	ltrs := []rune(guess)
+	for i := range ltrs {
+		if c := pat.At(i); (c == Green || c == Blue) && i < len(sol) {
+			ltrs[i] = sol[i]
+		}
+	}
+	return string(ltrs)
+}
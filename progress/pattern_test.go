@@ -0,0 +1,163 @@
+package progress
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pracj3am/wordle-solver/dict"
+)
+
+func TestScoreGuess(t *testing.T) {
+	tests := []struct {
+		guess, solution string
+		want            Pattern
+	}{
+		{"krava", "krava", "+++++"},
+		{"krava", "barva", " ..++"},
+		{"pivko", "krava", "  .. "},
+		// zelená spotřebuje i další výskyt písmena → modrá
+		{"aabbb", "abaaa", "*..  "},
+		// páruje se zleva včetně zelených, na pozdější x už výskyt nezbude
+		{"aaxxx", "xxxxa", ". ** "},
+		// diakritiku ScoreGuess nerozlišuje
+		{"kráva", "krava", "+++++"},
+		{"krava", "kráva", "+++++"},
+	}
+	for _, tt := range tests {
+		if got := ScoreGuess(tt.guess, tt.solution); got != tt.want {
+			t.Errorf("ScoreGuess(%q, %q) = %q, want %q", tt.guess, tt.solution, got, tt.want)
+		}
+	}
+}
+
+func TestParsePattern(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Pattern
+		pos  int // pozice chyby; want == "" a pos == -1 = chyba celého vzoru
+	}{
+		{"+*. ", NewPattern(Green, Blue, Orange, Grey), 0},
+		{"gbyx", NewPattern(Green, Blue, Orange, Grey), 0},
+		{"GBYX", NewPattern(Green, Blue, Orange, Grey), 0},
+		{"🟩🟦🟨⬜⬛", NewPattern(Green, Blue, Orange, Grey, Grey), 0},
+		{"⬜️🟩", NewPattern(Grey, Green), 0},
+		{"", "", -1},
+		{"++?", "", 2},
+		{"+ ", "", 1},
+		{"\t+", "", 0},
+	}
+	for _, tt := range tests {
+		got, err := ParsePattern(tt.in)
+		if tt.want != "" {
+			if err != nil || got != tt.want {
+				t.Errorf("ParsePattern(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+			}
+			continue
+		}
+		var perr *PatternError
+		if !errors.As(err, &perr) || perr.Pos != tt.pos {
+			t.Errorf("ParsePattern(%q) = %q, %v, want error at %d", tt.in, got, err, tt.pos)
+		}
+	}
+}
+
+func TestParsePatternN(t *testing.T) {
+	if pat, err := ParsePatternN("++ ..", 5); err != nil || pat != "++ .." {
+		t.Errorf("ParsePatternN: %q, %v", pat, err)
+	}
+	if _, err := ParsePatternN("++", 5); err == nil {
+		t.Errorf("ParsePatternN(%q, 5): want error", "++")
+	}
+}
+
+func testDictionary(t *testing.T) *dict.Dictionary {
+	t.Helper()
+	d, err := dict.LoadDictionaryFromReader(strings.NewReader("krava\nkráva\nbarva\nmleko\npivko\nvrána\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func words(p *Progress) []string {
+	var words []string
+	for dw := range p.Words() {
+		words = append(words, dw.Word)
+	}
+	slices.Sort(words)
+	return words
+}
+
+func TestApply(t *testing.T) {
+	d := testDictionary(t)
+	tests := []struct {
+		guess, solution string
+		want            []string
+	}{
+		{"barva", "kráva", []string{"krava", "kráva"}},
+		{"barva", "barva", []string{"barva"}},
+		{"pivko", "mleko", []string{"mleko"}},
+		{"mleko", "vrána", []string{"barva", "vrána"}},
+	}
+	for _, tt := range tests {
+		p := NewProgress(d.Size, d, FoldBase())
+		if err := p.Apply(tt.guess, ScoreGuess(tt.guess, tt.solution)); err != nil {
+			t.Errorf("Apply(%q) for %q: %v", tt.guess, tt.solution, err)
+			continue
+		}
+		if got := words(p); !slices.Equal(got, tt.want) {
+			t.Errorf("Apply(%q) for %q: words %q, want %q", tt.guess, tt.solution, got, tt.want)
+		}
+	}
+
+	p := NewProgress(d.Size, d, FoldBase())
+	if err := p.Apply("krav", "++++"); err == nil {
+		t.Errorf("Apply with a 4-letter guess: want error")
+	}
+	if err := p.Apply("krava", "++++"); err == nil {
+		t.Errorf("Apply with a 4-position pattern: want error")
+	}
+}
+
+// partitionWords = slovník s variantami lišícími se jen diakritikou.
+const partitionWords = "krava\nkráva\nbarva\nmleko\npivko\nvrána\nvrana\nhrana\nhraná\nbrána\nkosti\nkostí\nbabka\nbábka\nkobka\nlabuť\n"
+
+func TestPartition(t *testing.T) {
+	d, err := dict.LoadDictionaryFromReader(strings.NewReader(partitionWords), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rules := range []Rules{FoldBase(), StrictAccents()} {
+		fresh := NewProgress(d.Size, d, rules)
+		played := fresh.Clone()
+		played.Guess("mleko", "hraná")
+		for _, p := range []*Progress{fresh, played} {
+			_, _, left := p.WordsLeft(true)
+			for _, guess := range append(strings.Fields(partitionWords), "rabat", "ááááá") {
+				seen := 0
+				for pat, bucket := range p.Partition(guess, left) {
+					for _, group := range SplitRevealed(pat, bucket) {
+						counter, answers := p.CountWords(group)
+						for _, dw := range group {
+							seen++
+							if got := rules.ScoreGuess(guess, dw.Word); got != pat {
+								t.Errorf("%v: %q in bucket %q of %q, scores %q", rules, dw.Word, pat, guess, got)
+							}
+							q := p.Clone()
+							q.ResetRound()
+							q.Guess(guess, dw.Word)
+							if c, a := q.Count(); c != counter || a != answers {
+								t.Errorf("%v: %q for %q: group counts %d/%d, Guess+Count %d/%d", rules, guess, dw.Word, counter, answers, c, a)
+							}
+						}
+					}
+				}
+				if seen != len(left) {
+					t.Errorf("%v: Partition(%q) has %d words, want %d", rules, guess, seen, len(left))
+				}
+			}
+		}
+	}
+}
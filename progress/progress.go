@@ -1,7 +1,11 @@
 package progress
 
 import (
+	"fmt"
+	"iter"
 	"slices"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/pracj3am/wordle-solver/dict"
 )
@@ -15,18 +19,46 @@ type LetterFreq struct {
 	floor bool
 }
 
+// isExact = písmeno je ve slově právě f× (zelená bez floor bere Green taky
+// jako přesný počet).
+func (f LetterFreq) isExact() bool {
+	return f.exact || (!f.floor && f.f > 0)
+}
+
+// allows = slovo s n výskyty písmene omezení vyhoví.
+func (f LetterFreq) allows(n int) bool {
+	if f.isExact() {
+		return f.f == n
+	}
+	return !f.floor || f.f <= n
+}
+
+// merge spojí starší omezení f s novějším g (přesný počet má přednost před
+// minimem, z minim platí vyšší).
+func (f LetterFreq) merge(g LetterFreq) LetterFreq {
+	switch {
+	case g.isExact():
+		return LetterFreq{f: g.f, exact: true}
+	case f.isExact():
+		return LetterFreq{f: f.f, exact: true}
+	case f.floor || g.floor:
+		return LetterFreq{f: max(f.f, g.f), floor: true}
+	}
+	return LetterFreq{}
+}
+
 type PositionProgress struct {
 	solved  bool
 	písmeno rune
-	left    []bool // indexed by letter index
+	left    []bool // indexed by class (Rules)
 }
 
-func (pp *PositionProgress) valid(index int) bool {
-	if pp.solved && pp.písmeno == dict.Písmena[index] {
+func (pp *PositionProgress) valid(písmeno rune, class int) bool {
+	if pp.solved && pp.písmeno == písmeno {
 		return true
 	}
 
-	if !pp.solved && pp.left[dict.ConvIndex[index]] {
+	if !pp.solved && pp.left[class] {
 		return true
 	}
 
@@ -35,16 +67,28 @@ func (pp *PositionProgress) valid(index int) bool {
 
 type Progress struct {
 	words *dict.Dictionary
+	rules Rules
 	pos   []PositionProgress
-	freq  []LetterFreq // index by letter index: frequency of letters for unsolved positions
+	freq  []LetterFreq // index by class (Rules): frequency of letters for unsolved positions
+	past  []LetterFreq // index by class (Rules): frekvence z minulých kol (viz ResetRound)
 }
 
-func NewProgress(size int, words *dict.Dictionary) *Progress {
-	var progress = Progress{words: words}
+// NewProgress založí prázdný stav pro slova délky size; rules určují, která
+// písmena hra nerozlišuje (nulová hodnota = FoldBase), a platí pro abecedu
+// slovníku words. Bez slovníku (nil) platí pro dict.Czech, Words, Count
+// a WordsLeft nevrátí nic a Apply rozpor nepozná.
+func NewProgress(size int, words *dict.Dictionary, rules Rules) *Progress {
+	a := dict.Czech
+	if words != nil && words.Alphabet != nil {
+		a = words.Alphabet
+	}
+	var progress = Progress{words: words, rules: rules.bind(a)}
+	rules = progress.rules
 	progress.pos = make([]PositionProgress, size)
-	progress.freq = make([]LetterFreq, 26)
+	progress.freq = make([]LetterFreq, rules.size())
+	progress.past = make([]LetterFreq, rules.size())
 	for i := 0; i < size; i++ {
-		progress.pos[i].left = make([]bool, 26)
+		progress.pos[i].left = make([]bool, rules.size())
 		for j := range progress.pos[i].left {
 			progress.pos[i].left[j] = true
 		}
@@ -54,8 +98,9 @@ func NewProgress(size int, words *dict.Dictionary) *Progress {
 }
 
 func (p *Progress) Clone() *Progress {
-	var p1 = Progress{words: p.words}
-	p1.freq = p.freq
+	var p1 = Progress{words: p.words, rules: p.rules}
+	p1.freq = slices.Clone(p.freq)
+	p1.past = slices.Clone(p.past)
 	p1.pos = make([]PositionProgress, len(p.pos))
 	for i := range p.pos {
 		p1.pos[i] = p.pos[i]
@@ -69,6 +114,36 @@ func (p *Progress) Clone() *Progress {
 	return &p1
 }
 
+// Snapshot = úplná kopie stavu omezení (na rozdíl od Clone i s povolenými
+// písmeny zamčených pozic), ke které se jde vrátit přes Restore.
+type Snapshot struct {
+	pos  []PositionProgress
+	freq []LetterFreq
+	past []LetterFreq
+}
+
+func (p *Progress) Snapshot() Snapshot {
+	return Snapshot{pos: clonePos(p.pos), freq: slices.Clone(p.freq), past: slices.Clone(p.past)}
+}
+
+// Restore vrátí stav ze snapshotu; snapshot zůstává použitelný i dál.
+func (p *Progress) Restore(s Snapshot) {
+	p.pos = clonePos(s.pos)
+	p.freq = slices.Clone(s.freq)
+	p.past = slices.Clone(s.past)
+}
+
+func clonePos(pos []PositionProgress) []PositionProgress {
+	c := slices.Clone(pos)
+	for i := range c {
+		c[i].left = slices.Clone(pos[i].left)
+	}
+	return c
+}
+
+// ResetRound připraví stav na další tah: frekvence kola přesune do past
+// (platí dál, jen se už nepočítají jako omezení kola), písmena, která ve slově
+// nejsou, zakáže na všech volných pozicích.
 func (p *Progress) ResetRound() {
 	for j, f := range p.freq {
 		if f.exact && f.f == 0 {
@@ -78,176 +153,248 @@ func (p *Progress) ResetRound() {
 				}
 			}
 		}
+		p.past[j] = p.past[j].merge(f)
 	}
 
-	p.freq = make([]LetterFreq, 26)
+	p.freq = make([]LetterFreq, p.rules.size())
+}
+
+// Rules vrátí pravidla, podle kterých p porovnává písmena.
+func (p *Progress) Rules() Rules {
+	return p.rules
 }
 
-func (p *Progress) Grey(i int, letter rune) {
-	index := dict.Indexes[letter]
+// Alphabet vrátí abecedu, nad kterou p pracuje.
+func (p *Progress) Alphabet() *dict.Alphabet {
+	return p.rules.abc()
+}
+
+// class vrátí třídu písmene (s diakritikou i bez, malého i velkého) podle
+// pravidel p a písmeno zmenšené. Písmeno mimo abecedu je chyba volajícího.
+func (p *Progress) class(písmeno rune) (int, rune) {
+	písmeno = unicode.ToLower(písmeno)
+	index, ok := p.rules.index(písmeno)
+	if !ok {
+		panic(fmt.Sprintf("progress: unknown letter %q", písmeno))
+	}
+	return p.rules.classOf(index), písmeno
+}
+
+// Grey, Orange, GreenOrange a Green zapracují barvu jedné pozice; berou
+// libovolné písmeno abecedy (i velké), tj. tak, jak ho ukáže hra.
+func (p *Progress) Grey(i int, písmeno rune) {
+	index, _ := p.class(písmeno)
 	p.freq[index].exact = true
 	p.pos[i].left[index] = false
 }
 
-func (p *Progress) Orange(i int, letter rune) {
-	index := dict.Indexes[letter]
+func (p *Progress) Orange(i int, písmeno rune) {
+	index, _ := p.class(písmeno)
 	p.freq[index].f++
 	p.freq[index].floor = true
 	p.pos[i].left[index] = false
 }
 
 func (p *Progress) GreenOrange(i int, písmeno rune) {
-	index := dict.ConvIndex[dict.Indexes[písmeno]]
+	index, _ := p.class(písmeno)
 	p.freq[index].f++
 	p.freq[index].floor = true
 	p.Green(i, písmeno)
 }
 
 func (p *Progress) Green(i int, písmeno rune) {
-	index := dict.ConvIndex[dict.Indexes[písmeno]]
+	index, písmeno := p.class(písmeno)
 	p.freq[index].f++
 	p.pos[i].solved = true
 	p.pos[i].písmeno = písmeno
 }
 
-func (p *Progress) Guess(word, solution string) {
-	solPísm := make([]rune, 5)
-	solLtrs := make([]rune, 5)
-	solLtrsPos := make(map[rune][]int, 5)
-	i := 0
-	for _, písmeno := range solution {
-		r := dict.Conv[písmeno]
-		solPísm[i] = písmeno
-		solLtrs[i] = r
-		solLtrsPos[r] = append(solLtrsPos[r], i)
-		i++
-	}
-
-	i = 0
-	for _, písmeno := range word {
-		r := dict.Conv[písmeno]
-		if r == solLtrs[i] { // uhodnul jsem písmeno na pozici i
-			p.Green(i, solPísm[i])
-		} else { // písmeno r na pozici i je špatně
-			p.pos[i].left[dict.ConvIndex[dict.Indexes[písmeno]]] = false
-		}
-		i++
-	}
-	// zjistime, jestli pismeno nemá být oranžové
-	for _, písmeno := range word {
-		r := dict.Conv[písmeno]
-		var (
-			i, j     int
-			oranzova bool
-		)
-
-		for j, i = range solLtrsPos[r] {
-			if !p.pos[i].solved {
-				oranzova = true
-				break
-			}
-		}
-
-		// vyhodíme písmenko použité pro oranžovou, aby se nepoužilo víckrát
-		if j+1 < len(solLtrsPos[r]) {
-			solLtrsPos[r] = solLtrsPos[r][j+1:]
-		} else {
-			solLtrsPos[r] = nil
+// Apply zapracuje zpětnou vazbu pat na tip guess (převedený na dict.Canonical,
+// tj. velikost písmen ani NFD nehraje roli). Tip i vzor musí mít tolik pozic
+// jako slova slovníku. Kdyby po tahu nezbylo žádné slovo, vrátí
+// *ContradictionError a p nezmění.
+func (p *Progress) Apply(guess string, pat Pattern) error {
+	guess, err := p.Alphabet().Normalize(guess)
+	if err != nil {
+		return fmt.Errorf("progress: %w", err)
+	}
+	if n := utf8.RuneCountInString(guess); n != len(p.pos) {
+		return fmt.Errorf("progress: %q: %d letters, want %d", guess, n, len(p.pos))
+	}
+	if pat.Len() != len(p.pos) {
+		return fmt.Errorf("progress: pattern %q: %d positions, want %d", pat, pat.Len(), len(p.pos))
+	}
+	cs := turn(p.rules, guess, pat)
+	q := p.Clone()
+	for i, c := range cs {
+		q.add(c)
+		if q.empty() {
+			return p.contradiction(cs, i)
 		}
+	}
+	p.apply(cs)
+	return nil
+}
 
-		index := dict.ConvIndex[dict.Indexes[písmeno]]
-		if oranzova {
-			p.freq[index].f++
-			p.freq[index].floor = true
-		} else { // pismenko r se na jine pozici ve slove nevyskytuje
-			p.freq[index].exact = true
-		}
+func (p *Progress) apply(cs []Constraint) {
+	for _, c := range cs {
+		p.add(c)
 	}
+}
 
+// Guess zapracuje tip word proti známému řešení solution (bez kontroly, že
+// nějaké slovo zbude) a vrátí zpětnou vazbu, kterou přitom použil.
+func (p *Progress) Guess(word, solution string) Pattern {
+	pat := p.rules.ScoreGuess(word, solution)
+	p.apply(turn(p.rules, Reveal(word, solution, pat), pat))
+	return pat
 }
 
-func freq(i int, indexes []int) int {
+func freq(classes []int, i int, indexes []int) int {
 	freq := 0
 	for _, idx := range indexes {
-		if i == dict.ConvIndex[idx] {
+		if i == classes[idx] {
 			freq++
 		}
 	}
 	return freq
 }
 
+// valid = slovo s písmeny indexes splní frekvence kola i minulých kol
+// (zvlášť, takže tah v rozporu s minulými koly nepustí nic).
 func (p *Progress) valid(indexes ...int) bool {
 	for i, f := range p.freq {
-		if f.exact || (!f.floor && f.f > 0) {
-			if f.f != freq(i, indexes) {
-				return false
-			}
-		} else if f.floor {
-			if f.f > freq(i, indexes) {
-				return false
-			}
+		k := p.past[i]
+		if f == (LetterFreq{}) && k == (LetterFreq{}) {
+			continue
+		}
+		n := freq(p.rules.table, i, indexes)
+		if !f.allows(n) || !k.allows(n) {
+			return false
 		}
 	}
 
 	return true
 }
 
-func (p *Progress) WordsLeft(list bool) (int, int, []*dict.DictionaryWord) {
-	counter := 0
-	counterNotUsed := 0
-	wordsLeft := make([]*dict.DictionaryWord, 0)
-	uniqWords := make(map[string]bool)
-	uniqWordsNotUsed := make(map[string]bool)
-
-	for l1, w1 := range p.words.First {
-		if w1 == nil {
-			continue
+// bounds vrátí pro každou třídu frekvence, podle kterých se omezení
+// vypisují: kolo spojené s minulými koly (viz merge), a když si odporují
+// (tah v rozporu s minulými koly), obě zvlášť jako ve valid.
+func (p *Progress) bounds() [][]LetterFreq {
+	b := make([][]LetterFreq, len(p.freq))
+	for i, f := range p.freq {
+		k := p.past[i]
+		switch m := k.merge(f); {
+		case m.isExact() && !(k.allows(m.f) && f.allows(m.f)):
+			b[i] = []LetterFreq{k, f}
+		case m != (LetterFreq{}):
+			b[i] = []LetterFreq{m}
 		}
-		if p.pos[0].valid(l1) {
-			for l2, w2 := range w1.Next {
-				if w2 == nil {
-					continue
-				}
-				if p.pos[1].valid(l2) {
-					for l3, w3 := range w2.Next {
-						if w3 == nil {
-							continue
-						}
-						if p.pos[2].valid(l3) {
-							for l4, w4 := range w3.Next {
-								if w4 == nil {
-									continue
-								}
-								if p.pos[3].valid(l4) {
-									for l5, w5 := range w4.Next {
-										if w5 == nil {
-											continue
-										}
-										if p.pos[4].valid(l5) {
-											if p.valid(l1, l2, l3, l4, l5) {
-												dw := w5.Word
-												if list {
-													wordsLeft = append(wordsLeft, dw)
-												}
-												if !uniqWords[dw.WithoutDiacritics] {
-													uniqWords[dw.WithoutDiacritics] = true
-													counter++
-												}
-												if !dw.Used && !uniqWordsNotUsed[dw.WithoutDiacritics] {
-													uniqWordsNotUsed[dw.WithoutDiacritics] = true
-													counterNotUsed++
-												}
-											}
-										}
-									}
-								}
-							}
-						}
-					}
-				}
-			}
+	}
+	return b
+}
+
+// Words vrátí zbývající slova (i varianty lišící se jen diakritikou) v pořadí
+// trie; procházení se dá kdykoli ukončit (break). Slova vydává pod zámkem
+// slovníku, tělo cyklu ho proto nesmí volat ani měnit (viz dict.Dictionary.All).
+func (p *Progress) Words() iter.Seq[*dict.DictionaryWord] {
+	return func(yield func(*dict.DictionaryWord) bool) {
+		p.walk(yield)
+	}
+}
+
+func (p *Progress) walk(visit func(*dict.DictionaryWord) bool) {
+	if p.words == nil {
+		return
+	}
+	písmena, classes := p.Alphabet().Písmena, p.rules.table
+	p.words.Walk(
+		func(pos, index int) bool {
+			return pos < len(p.pos) && p.pos[pos].valid(písmena[index], classes[index])
+		},
+		func(indexes []int, dw *dict.DictionaryWord) bool {
+			return !p.valid(indexes...) || visit(dw)
+		},
+	)
+}
+
+// empty = nezbylo žádné slovo; bez slovníku to nejde poznat (false).
+func (p *Progress) empty() bool {
+	if p.words == nil {
+		return false
+	}
+	empty := true
+	p.walk(func(*dict.DictionaryWord) bool {
+		empty = false
+		return false
+	})
+	return empty
+}
+
+// counter počítá slova a možné odpovědi; varianty, které hra podle rules
+// nerozliší, jednou. Mapy na deduplikaci založí, až když narazí na slovo
+// s variantami.
+type counter struct {
+	rules                   Rules
+	counter, counterAnswers int
+	uniqWords               map[string]bool
+	uniqAnswers             map[string]bool
+}
+
+func (c *counter) add(dw *dict.DictionaryWord) {
+	if dw.Unique() {
+		c.counter++
+		if dw.Possible() {
+			c.counterAnswers++
 		}
+		return
+	}
+	if c.uniqWords == nil {
+		c.uniqWords = make(map[string]bool)
+		c.uniqAnswers = make(map[string]bool)
+	}
+	key := c.rules.Key(dw)
+	if !c.uniqWords[key] {
+		c.uniqWords[key] = true
+		c.counter++
+	}
+	if dw.Possible() && !c.uniqAnswers[key] {
+		c.uniqAnswers[key] = true
+		c.counterAnswers++
+	}
+}
+
+// CountWords spočítá slova jako Count, ale v daném seznamu (např. v jedné
+// skupině z Partition).
+func (p *Progress) CountWords(words []*dict.DictionaryWord) (int, int) {
+	c := counter{rules: p.rules}
+	for _, dw := range words {
+		c.add(dw)
 	}
+	return c.counter, c.counterAnswers
+}
+
+// Count vrátí počet zbývajících slov a z toho možných odpovědí (jako
+// WordsLeft, ale bez seznamu).
+func (p *Progress) Count() (int, int) {
+	c := counter{rules: p.rules}
+	p.walk(func(dw *dict.DictionaryWord) bool {
+		c.add(dw)
+		return true
+	})
+	return c.counter, c.counterAnswers
+}
+
+func (p *Progress) WordsLeft(list bool) (int, int, []*dict.DictionaryWord) {
+	c := counter{rules: p.rules}
+	wordsLeft := make([]*dict.DictionaryWord, 0)
+	p.walk(func(dw *dict.DictionaryWord) bool {
+		if list {
+			wordsLeft = append(wordsLeft, dw)
+		}
+		c.add(dw)
+		return true
+	})
 
-	return counter, counterNotUsed, wordsLeft
+	return c.counter, c.counterAnswers, wordsLeft
 }
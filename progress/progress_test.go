@@ -0,0 +1,22 @@
+package progress
+
+import "testing"
+
+func TestNoDictionary(t *testing.T) {
+	p := NewProgress(5, nil, FoldBase())
+	if err := p.Apply("krava", ScoreGuess("krava", "kráva")); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if c, a := p.Count(); c != 0 || a != 0 {
+		t.Errorf("Count() = %d, %d, want 0", c, a)
+	}
+	if c, a, words := p.WordsLeft(true); c != 0 || a != 0 || len(words) != 0 {
+		t.Errorf("WordsLeft() = %d, %d, %d words", c, a, len(words))
+	}
+	for dw := range p.Words() {
+		t.Errorf("Words: %q", dw.Word)
+	}
+	if !matches(p, "krava") || matches(p, "barva") {
+		t.Errorf("Regexp does not follow the applied guess")
+	}
+}
@@ -0,0 +1,243 @@
+package progress
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pracj3am/wordle-solver/dict"
+)
+
+// Rules = která písmena hra považuje za stejná (oranžová, šedá, frekvence
+// a zelená při porovnání tipu s řešením). Zelená pozice se vždy zamyká na
+// písmeno i s diakritikou, které hra odkryje.
+//
+// Nulová hodnota = FoldBase, tj. všechny varianty s diakritikou patří
+// k základnímu písmenu. FoldBase a StrictAccents platí pro libovolnou
+// abecedu (NewProgress je naváže na abecedu slovníku), Equivalence jen pro
+// tu, se kterou vznikla.
+type Rules struct {
+	name     string
+	alphabet *dict.Alphabet // nil = zatím nenavázaná (pak dict.Czech)
+	strict   bool
+	class    []int  // index písmene → třída; nil = podle strict
+	rep      []rune // třída → písmeno, kterým se třída vypisuje
+	table    []int  // index písmene → třída pro navázanou abecedu (bind)
+}
+
+// FoldBase = písmena se porovnávají bez diakritiky (výchozí pravidla).
+func FoldBase() Rules {
+	return Rules{}
+}
+
+// StrictAccents = každé písmeno s diakritikou je samostatné písmeno.
+func StrictAccents() Rules {
+	return Rules{name: "strict", strict: true}
+}
+
+// Equivalence = samostatná písmena abecedy a (nil = dict.Czech), jako
+// StrictAccents, kromě daných skupin, např. Equivalence(dict.Czech, "uúů", "ií").
+// Skupina smí obsahovat jen varianty jednoho základního písmene a každé
+// písmeno nanejvýš jednou.
+func Equivalence(a *dict.Alphabet, groups ...string) (Rules, error) {
+	if a == nil {
+		a = dict.Czech
+	}
+	if len(groups) == 0 {
+		r := StrictAccents()
+		r.alphabet = a
+		return r, nil
+	}
+
+	class := make([]int, a.Size())
+	for i := range class {
+		class[i] = i
+	}
+	grouped := make([]bool, a.Size())
+	for _, g := range groups {
+		first := -1
+		for _, písmeno := range g {
+			index, ok := a.Index(písmeno)
+			if !ok {
+				return Rules{}, fmt.Errorf("rules: %q: unknown letter %q", g, písmeno)
+			}
+			if grouped[index] {
+				return Rules{}, fmt.Errorf("rules: %q: letter %q is already in a group", g, písmeno)
+			}
+			grouped[index] = true
+			if first < 0 {
+				first = index
+			} else if a.ConvIndex[index] != a.ConvIndex[first] {
+				return Rules{}, fmt.Errorf("rules: %q: %q and %q have different base letters", g, a.Písmena[first], písmeno)
+			}
+			class[index] = class[first]
+		}
+	}
+
+	// třídy očíslujeme popořadě, písmenem třídy je její první písmeno v abecedě
+	r := Rules{alphabet: a, class: make([]int, len(class))}
+	number := make(map[int]int)
+	var members [][]rune
+	for index, c := range class {
+		n, ok := number[c]
+		if !ok {
+			n = len(r.rep)
+			number[c] = n
+			r.rep = append(r.rep, a.Písmena[index])
+			members = append(members, nil)
+		}
+		r.class[index] = n
+		members[n] = append(members[n], a.Písmena[index])
+	}
+
+	// jméno nezávisí na pořadí skupin ani písmen (skupiny po jednom písmenu
+	// nic nemění), takže stejná pravidla mají stejný String
+	var names []string
+	for _, m := range members {
+		if len(m) > 1 {
+			names = append(names, string(m))
+		}
+	}
+	if len(names) == 0 {
+		return Equivalence(a)
+	}
+	r.name = strings.Join(names, ",")
+	return r, nil
+}
+
+// ParseRules přečte pravidla zadaná textem: "fold", "strict" nebo skupiny
+// písmen abecedy a (nil = dict.Czech) oddělené čárkou ("uúů,ií").
+func ParseRules(a *dict.Alphabet, s string) (Rules, error) {
+	switch s {
+	case "", "fold":
+		return FoldBase(), nil
+	case "strict":
+		return StrictAccents(), nil
+	}
+	return Equivalence(a, strings.Split(s, ",")...)
+}
+
+// String vrátí pravidla v zápisu pro ParseRules (skupiny i písmena v nich
+// v pořadí abecedy).
+func (r Rules) String() string {
+	if r.name == "" {
+		return "fold"
+	}
+	return r.name
+}
+
+// bind naváže pravidla na abecedu a; pravidla z Equivalence pro jinou abecedu
+// jsou chyba volajícího.
+func (r Rules) bind(a *dict.Alphabet) Rules {
+	if r.alphabet != nil && r.alphabet != a {
+		panic(fmt.Sprintf("progress: rules %q are for a different alphabet", r))
+	}
+	r.alphabet = a
+	table := make([]int, a.Size())
+	for index := range table {
+		table[index] = r.classOf(index)
+	}
+	r.table = table
+	return r
+}
+
+// abc = abeceda, pro kterou pravidla platí.
+func (r Rules) abc() *dict.Alphabet {
+	if r.alphabet == nil {
+		return dict.Czech
+	}
+	return r.alphabet
+}
+
+// size = počet tříd (délka left a freq v Progress).
+func (r Rules) size() int {
+	switch {
+	case r.class != nil:
+		return len(r.rep)
+	case r.strict:
+		return r.abc().Size()
+	}
+	return r.abc().Bases
+}
+
+// classOf vrátí třídu písmene s indexem index (do abecedy).
+func (r Rules) classOf(index int) int {
+	switch {
+	case r.table != nil:
+		return r.table[index]
+	case r.class != nil:
+		return r.class[index]
+	case r.strict:
+		return index
+	}
+	return r.abc().ConvIndex[index]
+}
+
+// letter vrátí písmeno, kterým se vypisuje třída class.
+func (r Rules) letter(class int) rune {
+	if r.class != nil {
+		return r.rep[class]
+	}
+	return r.abc().Písmena[class] // základní písmena jsou v abecedě první
+}
+
+// index vrátí index písmena v abecedě pravidel.
+func (r Rules) index(písmeno rune) (int, bool) {
+	return r.abc().Index(písmeno)
+}
+
+// classOfLetter vrátí třídu, kterou písmeno letter vypisuje (viz letter).
+func (r Rules) classOfLetter(letter rune) (int, bool) {
+	index, ok := r.index(letter)
+	if !ok {
+		return 0, false
+	}
+	class := r.classOf(index)
+	return class, r.letter(class) == letter
+}
+
+// fold vrátí písmeno třídy, do které písmeno patří (neznámé vrátí beze změny).
+func (r Rules) fold(písmeno rune) rune {
+	index, ok := r.index(písmeno)
+	if !ok {
+		return písmeno
+	}
+	return r.letter(r.classOf(index))
+}
+
+// SameWord = hra by slova a a b nerozlišila (tip a by byl řešením b).
+func (r Rules) SameWord(a, b *dict.DictionaryWord) bool {
+	switch {
+	case a.WithoutDiacritics != b.WithoutDiacritics:
+		return false
+	case a.Word == b.Word:
+		return true
+	case r.class == nil:
+		return !r.strict
+	}
+	rb := []rune(b.Word)
+	i := 0
+	for _, písmeno := range a.Word {
+		if i >= len(rb) || r.fold(písmeno) != r.fold(rb[i]) {
+			return false
+		}
+		i++
+	}
+	return i == len(rb)
+}
+
+// Key = klíč slova; slova, která hra nerozliší, mají stejný klíč (pro FoldBase
+// je to WithoutDiacritics).
+func (r Rules) Key(dw *dict.DictionaryWord) string {
+	switch {
+	case r.class != nil:
+		return strings.Map(r.fold, dw.Word)
+	case r.strict:
+		return dw.Word
+	}
+	return dw.WithoutDiacritics
+}
+
+// ScoreGuess je ScoreGuess podle pravidel r.
+func (r Rules) ScoreGuess(guess, solution string) Pattern {
+	return newScorer(r, guess).score(solution)
+}
@@ -0,0 +1,80 @@
+package progress
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pracj3am/wordle-solver/dict"
+)
+
+func TestParseRules(t *testing.T) {
+	tests := []struct {
+		in, want string // want "" = chyba
+	}{
+		{"", "fold"},
+		{"fold", "fold"},
+		{"strict", "strict"},
+		{"uúů,ií", "ií,uúů"},
+		{"ií,uúů", "ií,uúů"},
+		{"ůúu,íi", "ií,uúů"},
+		{"uú,i", "uú"},
+		{"u,i", "strict"},
+		{"u1", ""},
+		{"uú,ú", ""},
+		{"ua", ""},
+	}
+	for _, tt := range tests {
+		r, err := ParseRules(nil, tt.in)
+		switch {
+		case tt.want == "" && err == nil:
+			t.Errorf("ParseRules(%q) = %v, want error", tt.in, r)
+		case tt.want != "" && (err != nil || r.String() != tt.want):
+			t.Errorf("ParseRules(%q) = %v, %v, want %s", tt.in, r, err, tt.want)
+		}
+	}
+}
+
+func TestEquivalence(t *testing.T) {
+	u, err := Equivalence(dict.Czech, "uúů")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		rules           Rules
+		guess, solution string
+		want            Pattern
+	}{
+		{StrictAccents(), "kráva", "krava", "++ +*"},
+		{StrictAccents(), "krava", "kráva", "++ ++"},
+		{StrictAccents(), "kúra", "kůra", "+ ++"},
+		{u, "kúra", "kůra", "++++"},
+		{u, "kúra", "kura", "++++"},
+		{u, "kráva", "krava", "++ +*"},
+		{u, "úkol", "kůly", ".. ."},
+	}
+	for _, tt := range tests {
+		if got := tt.rules.ScoreGuess(tt.guess, tt.solution); got != tt.want {
+			t.Errorf("%v.ScoreGuess(%q, %q) = %q, want %q", tt.rules, tt.guess, tt.solution, got, tt.want)
+		}
+	}
+}
+
+func TestRulesEncoding(t *testing.T) {
+	d, err := dict.LoadDictionaryFromReader(strings.NewReader("kůra\nkúra\nkura\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := ParseRules(d.Alphabet, "uúů,ií")
+	b, _ := ParseRules(d.Alphabet, "íi,ůúu")
+	data, err := json.Marshal(NewProgress(d.Size, d, a))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, NewProgress(d.Size, d, b)); err != nil {
+		t.Errorf("UnmarshalJSON with the same groups in another order: %v", err)
+	}
+	if err := json.Unmarshal(data, NewProgress(d.Size, d, StrictAccents())); err == nil {
+		t.Errorf("UnmarshalJSON with strict rules: want error")
+	}
+}
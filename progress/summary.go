@@ -0,0 +1,185 @@
+package progress
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Regexp vrátí regulární výraz (s diakritikou) odpovídající povoleným písmenům
+// na pozicích a frekvenční omezení, která výraz vyjádřit neumí. Slovo zbývá,
+// právě když výrazu vyhoví a splní i vrácená omezení (viz Explain).
+func (p *Progress) Regexp() (*regexp.Regexp, []Constraint) {
+	excluded := p.excluded()
+	var b strings.Builder
+	b.WriteByte('^')
+	for _, pp := range p.pos {
+		if pp.solved {
+			b.WriteRune(pp.písmeno)
+			continue
+		}
+		var set strings.Builder
+		for index, písmeno := range p.Alphabet().Písmena {
+			class := p.rules.classOf(index)
+			if pp.left[class] && !excluded[class] {
+				set.WriteRune(písmeno)
+			}
+		}
+		if set.Len() == 0 {
+			b.WriteString(`[^\x00-\x{10FFFF}]`) // nic
+		} else {
+			b.WriteString("[" + set.String() + "]")
+		}
+	}
+	b.WriteByte('$')
+
+	// přesně 0× vyjádří výraz vynecháním třídy, pokud ji nemá zamčená pozice
+	locked := make([]bool, p.rules.size())
+	for _, pp := range p.pos {
+		if pp.solved {
+			class, _ := p.class(pp.písmeno)
+			locked[class] = true
+		}
+	}
+	var cs []Constraint
+	for _, c := range p.freqConstraints() {
+		if class, _ := p.class(c.Letter); c.Kind != Exactly || c.Count > 0 || locked[class] {
+			cs = append(cs, c)
+		}
+	}
+	return regexp.MustCompile(b.String()), cs
+}
+
+// freqConstraints vrátí frekvence (aktuálního i minulých kol) jako omezení
+// (v pořadí tříd).
+func (p *Progress) freqConstraints() []Constraint {
+	var cs []Constraint
+	for i, fs := range p.bounds() {
+		for _, f := range fs {
+			switch {
+			case f.isExact():
+				cs = append(cs, Constraint{Kind: Exactly, Letter: p.rules.letter(i), Count: f.f})
+			case f.floor && f.f > 0:
+				cs = append(cs, Constraint{Kind: AtLeast, Letter: p.rules.letter(i), Count: f.f})
+			}
+		}
+	}
+	return cs
+}
+
+// excluded = třídy, které ve slově nejsou (přesně 0×) a na nezamčených
+// pozicích být nesmí.
+func (p *Progress) excluded() []bool {
+	excluded := make([]bool, p.rules.size())
+	for i, fs := range p.bounds() {
+		for _, f := range fs {
+			excluded[i] = excluded[i] || f.exact && f.f == 0
+		}
+	}
+	return excluded
+}
+
+// keyboard = česká klávesnice QWERTZ; písmena, která pravidla nerozlišují
+// od jiného nebo která v abecedě nejsou, Summary vynechá (a písmena abecedy,
+// která na ní chybí, přidá jako poslední řádek).
+var keyboard = []string{
+	"ěščřžýáíé",
+	"qwertzuiopú",
+	"asdfghjklů",
+	"yxcvbnmďťňó",
+}
+
+// Summary vrátí přehled omezení: zamčené pozice, písmena s počtem výskytů,
+// vyloučená písmena a klávesnici (velké = ve slově, · = vyloučené).
+func (p *Progress) Summary() string {
+	size := p.rules.size()
+	known := make([]bool, size)
+	excluded := p.excluded()
+	open := false
+	for _, pp := range p.pos {
+		if pp.solved {
+			class, _ := p.class(pp.písmeno)
+			known[class] = true
+		} else {
+			open = true
+		}
+	}
+	// třída, která na žádné volné pozici nesmí být a není ani zamčená
+	for class := range size {
+		if !open || known[class] {
+			continue
+		}
+		banned := true
+		for _, pp := range p.pos {
+			if !pp.solved && pp.left[class] {
+				banned = false
+				break
+			}
+		}
+		excluded[class] = excluded[class] || banned
+	}
+
+	var b strings.Builder
+	b.WriteString("positions:")
+	for _, pp := range p.pos {
+		if pp.solved {
+			fmt.Fprintf(&b, " %c", unicode.ToUpper(pp.písmeno))
+		} else {
+			b.WriteString(" _")
+		}
+	}
+
+	var counts []string
+	for _, c := range p.freqConstraints() {
+		if c.Count == 0 {
+			continue
+		}
+		class, _ := p.class(c.Letter)
+		known[class] = true
+		if c.Kind == Exactly {
+			counts = append(counts, fmt.Sprintf("%c =%d", c.Letter, c.Count))
+		} else {
+			counts = append(counts, fmt.Sprintf("%c ≥%d", c.Letter, c.Count))
+		}
+	}
+	b.WriteString("\nletters:")
+	if len(counts) > 0 {
+		b.WriteString("   " + strings.Join(counts, ", "))
+	}
+
+	b.WriteString("\nexcluded:")
+	sep := "  "
+	for class, ok := range excluded {
+		if ok {
+			fmt.Fprintf(&b, "%s%c", sep, p.rules.letter(class))
+			sep = " "
+		}
+	}
+
+	shown := make([]bool, size)
+	rows := 0
+	for _, row := range append(keyboard, string(p.Alphabet().Písmena)) {
+		var keys []string
+		for _, písmeno := range row {
+			class, ok := p.rules.classOfLetter(písmeno)
+			if !ok || shown[class] {
+				continue
+			}
+			shown[class] = true
+			switch {
+			case known[class]:
+				keys = append(keys, string(unicode.ToUpper(písmeno)))
+			case excluded[class]:
+				keys = append(keys, "·")
+			default:
+				keys = append(keys, string(písmeno))
+			}
+		}
+		if len(keys) > 0 {
+			b.WriteString("\n" + strings.Repeat(" ", rows) + strings.Join(keys, " "))
+			rows++
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package progress
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRegexp(t *testing.T) {
+	d := testDictionary(t)
+	p := NewProgress(d.Size, d, FoldBase())
+	const all = "[abcdefghijklmnopqrstuvwxyzáčďéěíňóřšťúůýž]"
+	if re, cs := p.Regexp(); re.String() != "^"+all+all+all+all+all+"$" || len(cs) != 0 {
+		t.Errorf("new: Regexp() = %v %v", re, cs)
+	}
+
+	if err := p.Apply("barva", ScoreGuess("barva", "kráva")); err != nil {
+		t.Fatal(err)
+	}
+	re, cs := p.Regexp()
+	want := "^[acdefghijklmnopqrstuvwxyzáčďéěíňóřšťúůýž]" + // b vyloučené všude
+		"[cdefghijklmnopqrstuvwxyzčďéěíňóřšťúůýž]" + // oranžové a (s variantami)
+		"[acdefghijklmnopqstuvwxyzáčďéěíňóšťúůýž]" + // oranžové r
+		"va$"
+	if re.String() != want {
+		t.Errorf("Regexp() = %s, want %s", re, want)
+	}
+	wantCs := []Constraint{
+		{Kind: Exactly, Letter: 'a', Count: 2},
+		{Kind: AtLeast, Letter: 'r', Count: 1},
+		{Kind: Exactly, Letter: 'v', Count: 1},
+	}
+	if !slices.Equal(cs, wantCs) {
+		t.Errorf("Regexp() constraints %v, want %v", cs, wantCs)
+	}
+	for word, want := range map[string]bool{"kráva": true, "krava": true, "barva": false, "vrána": false, "zrrva": false} {
+		if got := matches(p, word); got != want {
+			t.Errorf("Regexp matches %q: %v, want %v", word, got, want)
+		}
+	}
+}
+
+func TestSummary(t *testing.T) {
+	d := testDictionary(t)
+	p := NewProgress(d.Size, d, FoldBase())
+	want := `positions: _ _ _ _ _
+letters:
+excluded:
+q w e r t z u i o p
+ a s d f g h j k l
+  y x c v b n m`
+	if got := p.Summary(); got != want {
+		t.Errorf("new: Summary() =\n%s\nwant\n%s", got, want)
+	}
+
+	if err := p.Apply("barva", ScoreGuess("barva", "kráva")); err != nil {
+		t.Fatal(err)
+	}
+	want = `positions: _ _ _ V A
+letters:   a =2, r ≥1, v =1
+excluded:  b
+q w e R t z u i o p
+ A s d f g h j k l
+  y x c V · n m`
+	if got := p.Summary(); got != want {
+		t.Errorf("Summary() =\n%s\nwant\n%s", got, want)
+	}
+
+	s, err := ParseRules(d.Alphabet, "strict")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p = NewProgress(d.Size, d, s)
+	if err := p.Apply("vrána", s.ScoreGuess("vrána", "kráva")); err != nil {
+		t.Fatal(err)
+	}
+	want = `positions: _ R Á _ A
+letters:   a =1, r =1, v ≥1, á =1
+excluded:  n
+ě š č ř ž ý Á í é
+ q w e R t z u i o p ú
+  A s d f g h j k l ů
+   y x c V b · m ď ť ň ó`
+	if got := p.Summary(); got != want {
+		t.Errorf("strict: Summary() =\n%s\nwant\n%s", got, want)
+	}
+}